@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 	"sync"
 
 	"github.com/xwb1989/sqlparser"
@@ -119,15 +120,33 @@ func parseSQL(sql string) (fileName string, content []byte, err error) {
 
 	tableName := createTableStmt.NewName.Name.String()
 	parser := NewParser(tableName)
+	for _, index := range createTableStmt.TableSpec.Indexes {
+		if !index.Info.Unique || index.Info.Primary {
+			continue
+		}
+		uniqueKey := make([]string, 0, len(index.Columns))
+		for _, column := range index.Columns {
+			uniqueKey = append(uniqueKey, column.Column.String())
+		}
+		parser.UniqueKeys = append(parser.UniqueKeys, uniqueKey)
+	}
 	for _, column := range createTableStmt.TableSpec.Columns {
 		c := &Column{
-			Name:  column.Name.String(),
-			Alias: column.Name.String(),
-			Type:  column.Type.Type,
+			Name:    column.Name.String(),
+			Alias:   column.Name.String(),
+			Type:    column.Type.Type,
+			NotNull: bool(column.Type.NotNull),
+		}
+		for _, value := range column.Type.EnumValues {
+			c.EnumValues = append(c.EnumValues, strings.Trim(value, "'"))
 		}
 		if column.Type.KeyOpt == 1 || primaryKeyMap[column.Name.String()] {
 			c.IsPrimaryKey = true
 		}
+		// UNIQUE, UNIQUE KEY
+		if column.Type.KeyOpt == 3 || column.Type.KeyOpt == 4 {
+			parser.UniqueKeys = append(parser.UniqueKeys, []string{c.Name})
+		}
 		parser.AddColumns(c)
 	}
 
@@ -20,6 +20,9 @@ import (
 
 const (
 	FieldType{{ .NodeName }} = "{{ .NodeNameLower }}"
+	{{- range .Enums }}
+	FieldType{{ .Type }} = "{{ .Type }}"
+	{{- end }}
 )
 
 type {{ .NodeName }} struct {
@@ -33,6 +36,9 @@ var _ core.Node = (*{{ .NodeName }})(nil)
 func New{{ .NodeName }}() (d *{{ .NodeName }}) {
 	d = &{{ .NodeName }}{}
 	d.SqlAdapter = adapter.NewDefaultSqlAdapter("{{ .TableName }}", d.initItemTable(), d)
+	{{- range .UniqueKeys }}
+	d.Adapter().AddUniqueKey({{ range $i, $column := . }}{{ if $i }}, {{ end }}"{{ $column }}"{{ end }})
+	{{- end }}
 	return
 }
 
@@ -42,7 +48,7 @@ func (d *{{ .NodeName }}) initItemTable() []*adapter.Column {
 
 	{{ range .PrimaryColumns }}
 	column = &adapter.Column{
-		Type:  "",
+		Type:  "{{ .ColumnType }}",
 		Name:  "{{ .Name }}",
 		Alias: "{{ .Alias }}",
 	}
@@ -52,7 +58,7 @@ func (d *{{ .NodeName }}) initItemTable() []*adapter.Column {
 
 	{{ range .Columns }}
 	columns = append(columns, &adapter.Column{
-		Type:  "",
+		Type:  "{{ .ColumnType }}",
 		Name:  "{{ .Name }}",
 		Alias: "{{ .Alias }}",
 	})
@@ -78,7 +84,7 @@ func (d *{{ .NodeName }}) BuildFields() []*core.Field {
 
 	{{ range .Fields }}
 	fields = append(fields,
-		core.NewNodeField("{{ .Name }}", core.FieldType{{ .Type }}),
+		core.NewNodeField("{{ .Name }}", {{ if .Enum }}FieldType{{ .Type }}{{ else }}core.FieldType{{ .Type }}{{ end }}){{ if .NonNull }}.NonNull(){{ end }},
 	)
 	{{ end }}
 
@@ -87,6 +93,23 @@ func (d *{{ .NodeName }}) BuildFields() []*core.Field {
 	return fields
 }
 
+{{ if .Enums }}
+// BuildEnums declares the enums of the ENUM columns.
+func (d *{{ .NodeName }}) BuildEnums() []*core.Enum {
+	return []*core.Enum{
+		{{- range .Enums }}
+		{
+			Type: FieldType{{ .Type }},
+			Values: []*core.EnumValue{
+				{{- range .Values }}
+				{Name: "{{ .Name }}", Value: {{ printf "%q" .Value }}},
+				{{- end }}
+			},
+		},
+		{{- end }}
+	}
+}
+{{ end }}
 // DEMO: custom field and resolver
 func (d *{{ .NodeName }}) demoField() *core.Field {
 	field := core.NewNodeField("demo", core.FieldTypeString)
@@ -109,6 +132,9 @@ type Parser struct {
 	PrimaryColumns []*Column
 	Columns        []*Column
 	Fields         []*Field
+	Enums          []*Enum
+	// UniqueKeys 唯一索引的列名, 生成 xByY 查询.
+	UniqueKeys [][]string
 }
 
 func NewParser(tableName string) *Parser {
@@ -121,6 +147,22 @@ func NewParser(tableName string) *Parser {
 }
 
 func (p *Parser) AddColumns(column *Column) {
+	var fieldType core.FieldType
+	isEnum := len(column.EnumValues) > 0
+	if !isEnum {
+		fieldType = column.SwitchType()
+	} else {
+		// ENUM 列生成同名的枚举类型, 例如 user.status -> UserStatus.
+		fieldType = core.FieldType(p.NodeName + ToCamelCase(column.Name))
+		enum := &Enum{Type: string(fieldType)}
+		for _, value := range column.EnumValues {
+			enum.Values = append(enum.Values, &EnumValue{Name: EnumValueName(value), Value: value})
+		}
+		p.Enums = append(p.Enums, enum)
+	}
+	if fieldType != "interface{}" {
+		column.ColumnType = string(fieldType)
+	}
 	if column.IsPrimaryKey {
 		p.PrimaryColumns = append(p.PrimaryColumns, column)
 	} else {
@@ -128,8 +170,10 @@ func (p *Parser) AddColumns(column *Column) {
 	}
 
 	p.Fields = append(p.Fields, &Field{
-		Name: column.Name,
-		Type: column.SwitchType(),
+		Name:    column.Name,
+		Type:    fieldType,
+		NonNull: column.NotNull || column.IsPrimaryKey,
+		Enum:    isEnum,
 	})
 	return
 }
@@ -157,16 +201,36 @@ type Column struct {
 	Alias        string
 	Type         string
 	IsPrimaryKey bool
+	// NotNull 列声明了 NOT NULL, 生成的字段为 non-null.
+	NotNull bool
+	// ColumnType adapter.ColumnType 的值, 与 field 的类型同名, 未知类型为空.
+	ColumnType string
+	// EnumValues ENUM 列的取值.
+	EnumValues []string
 }
 
 func (c *Column) SwitchType() core.FieldType {
 	switch strings.ToUpper(c.Type) {
-	case "INT", "BIGINT", "SMALLINT", "TINYINT":
+	case "INT", "INTEGER", "MEDIUMINT", "SMALLINT", "TINYINT", "YEAR":
 		return core.FieldTypeInt
-	case "VARCHAR", "TEXT", "CHAR":
+	case "BIGINT":
+		return core.FieldTypeBigInt
+	case "VARCHAR", "TEXT", "CHAR", "TINYTEXT", "MEDIUMTEXT", "LONGTEXT":
 		return core.FieldTypeString
-	case "DECIMAL", "FLOAT":
+	case "FLOAT", "DOUBLE", "REAL":
 		return core.FieldTypeFloat
+	case "DECIMAL", "NUMERIC":
+		return core.FieldTypeDecimal
+	case "BOOL", "BOOLEAN":
+		return core.FieldTypeBoolean
+	case "DATETIME", "TIMESTAMP":
+		return core.FieldTypeDateTime
+	case "DATE":
+		return core.FieldTypeDate
+	case "TIME":
+		return core.FieldTypeTime
+	case "JSON":
+		return core.FieldTypeJSON
 	// ... 其他数据类型
 	default:
 		fmt.Printf("unknown data type: %s, please summit issue to https://github.com/Finovate/go-gql-builder/issues\n", c.Type)
@@ -175,6 +239,19 @@ func (c *Column) SwitchType() core.FieldType {
 }
 
 type Field struct {
-	Name string
-	Type core.FieldType
+	Name    string
+	Type    core.FieldType
+	NonNull bool
+	// Enum 字段的类型是生成的枚举, 常量定义在生成的文件中.
+	Enum bool
+}
+
+type Enum struct {
+	Type   string
+	Values []*EnumValue
+}
+
+type EnumValue struct {
+	Name  string
+	Value string
 }
@@ -64,3 +64,21 @@ func ToLowerCamelCase(str string) string {
 	// Join the words back together
 	return strings.Join(words, "")
 }
+
+// EnumValueName converts a value of an ENUM column into a GraphQL enum value name, e.g. in-progress -> IN_PROGRESS
+func EnumValueName(value string) string {
+	var builder strings.Builder
+	for _, r := range strings.ToUpper(value) {
+		if (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') || r == '_' {
+			builder.WriteRune(r)
+		} else {
+			builder.WriteRune('_')
+		}
+	}
+
+	name := builder.String()
+	if name == "" || (name[0] >= '0' && name[0] <= '9') {
+		name = "_" + name
+	}
+	return name
+}
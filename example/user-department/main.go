@@ -14,6 +14,7 @@ func InitGraphQL() (http.Handler, error) {
 	core.DefaultRegistry().Register(model.NewDepartmentDelegate())
 
 	core.DefaultRegistry().SetDB(conf.C().Mysql.GetDB())
+	core.DefaultRegistry().EnableMutation()
 	return core.DefaultRegistry().BuildHandler()
 }
 
@@ -38,9 +38,16 @@ func (d *DepartmentDelegate) BuildFields() []*core.Field {
 	fields = append(fields,
 		core.NewNodeField("test", core.FieldTypeString),
 	)
+
+	fields = append(fields, d.usersField())
 	return fields
 }
 
+// usersField department.id <- user.department_id
+func (d *DepartmentDelegate) usersField() *core.Field {
+	return d.Adapter().HasMany("users", FieldTypeUser, "id", "department_id")
+}
+
 func (d *DepartmentDelegate) IsList() bool {
 	return true
 }
@@ -53,11 +60,13 @@ func NewDepartmentDelegate() (d *DepartmentDelegate) {
 
 func (d *DepartmentDelegate) initItemTable() []*adapter.Column {
 	columns := make([]*adapter.Column, 0)
-	columns = append(columns, &adapter.Column{
+	column := &adapter.Column{
 		Type:  "",
 		Name:  "id",
 		Alias: "id",
-	})
+	}
+	column.SetPrimaryKey()
+	columns = append(columns, column)
 	columns = append(columns, &adapter.Column{
 		Type:  "",
 		Name:  "name",
@@ -1,8 +1,6 @@
 package model
 
 import (
-	"github.com/graphql-go/graphql"
-
 	"github.com/Finovate/go-gql-builder/pkg/adapter"
 	"github.com/Finovate/go-gql-builder/pkg/core"
 	"github.com/Finovate/go-gql-builder/pkg/core/argument"
@@ -46,6 +44,11 @@ func (d *UserDelegate) initItemTable() []*adapter.Column {
 		Name:  "email",
 		Alias: "email",
 	})
+	columns = append(columns, &adapter.Column{
+		Type:  "",
+		Name:  "department_id",
+		Alias: "departmentId",
+	})
 
 	return columns
 }
@@ -79,12 +82,7 @@ func (d *UserDelegate) BuildFields() []*core.Field {
 	return fields
 }
 
+// departmentField user.department_id -> department.id
 func (d *UserDelegate) departmentField() *core.Field {
-	field := core.NewNodeField("department", FieldTypeDepartment)
-	field.SetResolver(func(p graphql.ResolveParams) (interface{}, error) {
-		// TODO 可以考虑封装一个Thunk，这个resolver只拿主键，然后外层的方法再去查具体数据
-		// github.com/graphql-go/graphql@v0.8.1/executor.go:754
-		return []map[string]interface{}{{"id": "1", "name": "Example Product", "price": 99.99}}, nil
-	})
-	return field
+	return d.Adapter().BelongsTo("department", FieldTypeDepartment, "department_id", "id")
 }
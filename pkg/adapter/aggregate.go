@@ -0,0 +1,282 @@
+package adapter
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/graphql-go/graphql"
+
+	sqlArgument "github.com/Finovate/go-gql-builder/pkg/adapter/internal/argument"
+)
+
+const (
+	aggregateGroupByArgument = "groupBy"
+	aggregateHavingArgument  = "having"
+
+	aggregateGroupField = "group"
+	aggregateCountField = "count"
+)
+
+// aggregateFunctions the SQL functions of the numeric columns, avg is always Float,
+// sum is Float except for the exact BigInt and Decimal columns, see sumType, min and max keep the type of the column.
+var aggregateFunctions = []string{"sum", "avg", "min", "max"}
+
+// numericColumns returns the columns whose values can be aggregated, the Int, BigInt, Float and Decimal columns.
+func (d *DefaultSqlAdapter) numericColumns() []*Column {
+	columns := make([]*Column, 0)
+	for _, column := range d.tableColumns {
+		switch column.Type {
+		case Int, BigInt, Float, Decimal:
+			columns = append(columns, column)
+		}
+	}
+	return columns
+}
+
+// sumType returns the type of the sum of a numeric column, the BigInt and Decimal sums keep their scalar,
+// so no precision is lost, the Int and Float sums are Float, since the sum of Int may exceed 32 bits.
+func (d *DefaultSqlAdapter) sumType(column *Column) graphql.Input {
+	switch column.Type {
+	case BigInt, Decimal:
+		return d.inputType(column.Type)
+	default:
+		return graphql.Float
+	}
+}
+
+// DisableAggregate leaves the xAggregate field of the table out of the schema.
+func (d *DefaultSqlAdapter) DisableAggregate() {
+	d.aggregateDisabled = true
+}
+
+// buildAggregate generates the xAggregate field of the table, e.g.
+// usersAggregate(filter: UserFilter, groupBy: [UserAggregateGroupBy!], having: UserAggregateHaving): [UserAggregate!]!
+// Every element is a group of rows, with the values of the groupBy columns, the count and the numeric aggregates.
+func (d *DefaultSqlAdapter) buildAggregate() (*graphql.Field, error) {
+	name := typeName(d.node.Type())
+
+	groupValues := make(graphql.EnumValueConfigMap, len(d.tableColumns))
+	groupFields := make(graphql.Fields, len(d.tableColumns))
+	for _, column := range d.tableColumns {
+		groupValues[column.Alias] = &graphql.EnumValueConfig{Value: column.Alias}
+		groupFields[column.Alias] = &graphql.Field{Type: d.inputType(column.Type)}
+	}
+	groupBy := graphql.NewEnum(graphql.EnumConfig{
+		Name:        name + "AggregateGroupBy",
+		Description: fmt.Sprintf("Columns of %s the rows can be grouped by", name),
+		Values:      groupValues,
+	})
+
+	aggregateFields := graphql.Fields{
+		aggregateGroupField: &graphql.Field{
+			Type: graphql.NewObject(graphql.ObjectConfig{
+				Name:        name + "AggregateGroup",
+				Description: "values of the groupBy columns, the other columns are null",
+				Fields:      groupFields,
+			}),
+		},
+		aggregateCountField: &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+	}
+	havingFields := graphql.InputObjectConfigFieldMap{
+		aggregateCountField: &graphql.InputObjectFieldConfig{Type: sqlArgument.OperatorInputType(graphql.Int)},
+	}
+
+	numeric := d.numericColumns()
+	if len(numeric) > 0 {
+		sumFields := make(graphql.Fields, len(numeric))
+		numberFields := make(graphql.Fields, len(numeric))
+		valueFields := make(graphql.Fields, len(numeric))
+		sumFilters := make(graphql.InputObjectConfigFieldMap, len(numeric))
+		numberFilters := make(graphql.InputObjectConfigFieldMap, len(numeric))
+		valueFilters := make(graphql.InputObjectConfigFieldMap, len(numeric))
+		for _, column := range numeric {
+			sumFields[column.Alias] = &graphql.Field{Type: d.sumType(column)}
+			numberFields[column.Alias] = &graphql.Field{Type: graphql.Float}
+			valueFields[column.Alias] = &graphql.Field{Type: d.inputType(column.Type)}
+			sumFilters[column.Alias] = &graphql.InputObjectFieldConfig{Type: sqlArgument.OperatorInputType(d.sumType(column))}
+			numberFilters[column.Alias] = &graphql.InputObjectFieldConfig{Type: sqlArgument.OperatorInputType(graphql.Float)}
+			valueFilters[column.Alias] = &graphql.InputObjectFieldConfig{Type: sqlArgument.OperatorInputType(d.inputType(column.Type))}
+		}
+		sums := graphql.NewObject(graphql.ObjectConfig{Name: name + "AggregateSums", Fields: sumFields})
+		numbers := graphql.NewObject(graphql.ObjectConfig{Name: name + "AggregateNumbers", Fields: numberFields})
+		values := graphql.NewObject(graphql.ObjectConfig{Name: name + "AggregateValues", Fields: valueFields})
+		sumsFilter := graphql.NewInputObject(graphql.InputObjectConfig{Name: name + "AggregateSumsFilter", Fields: sumFilters})
+		numbersFilter := graphql.NewInputObject(graphql.InputObjectConfig{Name: name + "AggregateNumbersFilter", Fields: numberFilters})
+		valuesFilter := graphql.NewInputObject(graphql.InputObjectConfig{Name: name + "AggregateValuesFilter", Fields: valueFilters})
+
+		for _, function := range aggregateFunctions {
+			switch function {
+			case "sum":
+				aggregateFields[function] = &graphql.Field{Type: graphql.NewNonNull(sums)}
+				havingFields[function] = &graphql.InputObjectFieldConfig{Type: sumsFilter}
+			case "avg":
+				aggregateFields[function] = &graphql.Field{Type: graphql.NewNonNull(numbers)}
+				havingFields[function] = &graphql.InputObjectFieldConfig{Type: numbersFilter}
+			default:
+				aggregateFields[function] = &graphql.Field{Type: graphql.NewNonNull(values)}
+				havingFields[function] = &graphql.InputObjectFieldConfig{Type: valuesFilter}
+			}
+		}
+	}
+
+	args := graphql.FieldConfigArgument{
+		aggregateGroupByArgument: &graphql.ArgumentConfig{
+			Type:        graphql.NewList(graphql.NewNonNull(groupBy)),
+			Description: "the columns the rows are grouped by, all rows form a single group when it is empty",
+		},
+		aggregateHavingArgument: &graphql.ArgumentConfig{
+			Type: graphql.NewInputObject(graphql.InputObjectConfig{
+				Name:        name + "AggregateHaving",
+				Description: "conditions on the aggregates of the groups, they are joined with AND",
+				Fields:      havingFields,
+			}),
+		},
+	}
+	if filter, ok := d.node.GetRegistry().GetArgs(d.node.Type())[sqlArgument.FilterArgumentType]; ok {
+		args[sqlArgument.FilterArgumentType] = filter
+	}
+
+	return &graphql.Field{
+		Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(graphql.NewObject(graphql.ObjectConfig{
+			Name:        name + "Aggregate",
+			Description: fmt.Sprintf("Aggregates of a group of %s", name),
+			Fields:      aggregateFields,
+		})))),
+		Args:    args,
+		Resolve: d.resolveAggregate,
+	}, nil
+}
+
+func (d *DefaultSqlAdapter) resolveAggregate(p graphql.ResolveParams) (interface{}, error) {
+	groups := make([]*Column, 0)
+	if items, ok := p.Args[aggregateGroupByArgument].([]interface{}); ok {
+		for _, item := range items {
+			column, ok := d.columnsByAlias[fmt.Sprint(item)]
+			if !ok {
+				return nil, fmt.Errorf("groupBy argument contains unknown field %v", item)
+			}
+			groups = append(groups, column)
+		}
+	}
+
+	// the results are keyed by <function>__<alias>, so they never clash with the column aliases.
+	selects := []string{fmt.Sprintf("COUNT(*) AS %s", d.quote(aggregateCountField))}
+	groupColumns := make([]string, len(groups))
+	for i, column := range groups {
+		groupColumns[i] = d.quote(column.Name)
+		selects = append(selects, fmt.Sprintf("%s AS %s", d.quote(column.Name), d.quote(aggregateGroupField+"__"+column.Alias)))
+	}
+	for _, function := range aggregateFunctions {
+		for _, field := range nestedFields(p, function) {
+			column, ok := d.columnsByAlias[field.Name.Value]
+			if !ok {
+				continue
+			}
+			selects = append(selects, fmt.Sprintf("%s(%s) AS %s",
+				strings.ToUpper(function), d.quote(column.Name), d.quote(function+"__"+column.Alias)))
+		}
+	}
+
+	qc := sqlArgument.NewQueryClauses(strings.Join(selects, ","), d.quote(d.tableName))
+	if err := d.applyFilter(qc, p.Args); err != nil {
+		return nil, err
+	}
+	if len(groups) > 0 {
+		qc.SetGroupBy(strings.Join(groupColumns, ","))
+		qc.SetOrderBy(strings.Join(groupColumns, ","))
+	}
+	if having, ok := p.Args[aggregateHavingArgument].(map[string]interface{}); ok {
+		condition, args, err := d.havingCondition(having)
+		if err != nil {
+			return nil, err
+		}
+		qc.SetHaving(condition, args...)
+	}
+
+	rows, err := d.query(p.Context, qc)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]map[string]interface{}, len(rows))
+	for i, row := range rows {
+		result := map[string]interface{}{
+			aggregateGroupField: make(map[string]interface{}),
+			aggregateCountField: row[aggregateCountField],
+		}
+		for _, function := range aggregateFunctions {
+			result[function] = make(map[string]interface{})
+		}
+		for key, value := range row {
+			if function, alias, ok := strings.Cut(key, "__"); ok {
+				result[function].(map[string]interface{})[alias] = value
+			}
+		}
+		results[i] = result
+	}
+	return results, nil
+}
+
+// havingCondition converts the having argument into the condition on the aggregates,
+// e.g. having: { count: { gt: 1 }, sum: { age: { lt: 100 } } } -> COUNT(*) > ? AND SUM(age) < ?
+func (d *DefaultSqlAdapter) havingCondition(having map[string]interface{}) (string, []interface{}, error) {
+	conditions := make([]string, 0)
+	args := make([]interface{}, 0)
+	addConditions := func(expr string, input interface{}) error {
+		operationMap, ok := input.(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("having argument for %s must be a map[string]interface{}", expr)
+		}
+		for _, op := range sqlArgument.SortedKeys(operationMap) {
+			operation, err := sqlArgument.OperationFactory(op, expr, operationMap[op])
+			if err != nil {
+				return err
+			}
+			condition, opArgs := operation.ToSql(d.sqlDialect())
+			// skipping it would select the groups the client meant to exclude.
+			if strings.TrimSpace(condition) == "" {
+				return fmt.Errorf("having argument operator %s of %s renders no condition", op, expr)
+			}
+			conditions = append(conditions, condition)
+			args = append(args, opArgs...)
+		}
+		return nil
+	}
+
+	for _, function := range sqlArgument.SortedKeys(having) {
+		if function == aggregateCountField {
+			if err := addConditions("COUNT(*)", having[function]); err != nil {
+				return "", nil, err
+			}
+			continue
+		}
+
+		if !isAggregateFunction(function) {
+			return "", nil, fmt.Errorf("having argument contains unknown aggregate %s", function)
+		}
+		columns, ok := having[function].(map[string]interface{})
+		if !ok {
+			return "", nil, fmt.Errorf("having argument for %s must be a map[string]interface{}", function)
+		}
+		for _, alias := range sqlArgument.SortedKeys(columns) {
+			column, ok := d.columnsByAlias[alias]
+			if !ok {
+				return "", nil, fmt.Errorf("having argument contains unknown field %s", alias)
+			}
+			expr := fmt.Sprintf("%s(%s)", strings.ToUpper(function), d.quote(column.Name))
+			if err := addConditions(expr, columns[alias]); err != nil {
+				return "", nil, err
+			}
+		}
+	}
+	return strings.Join(conditions, " AND "), args, nil
+}
+
+func isAggregateFunction(name string) bool {
+	for _, function := range aggregateFunctions {
+		if function == name {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,197 @@
+package adapter
+
+import (
+	"database/sql/driver"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Finovate/go-gql-builder/pkg/core"
+)
+
+// newAggregateHandler returns the handler of the table order (id Int primary key, customer, age Int,
+// total BigInt, amount Decimal), every query returns the row of values.
+func newAggregateHandler(t *testing.T, columns []string, values ...[]driver.Value) (*stubDB, http.Handler) {
+	stub, db := newStubDB(t, func(string, []interface{}) ([]string, [][]driver.Value) {
+		return columns, values
+	})
+	id := &Column{Type: Int, Name: "id"}
+	id.SetPrimaryKey()
+	registry := core.NewRegistry()
+	registry.Register(newTestNode("orders", "order", "order", id, &Column{Name: "customer"},
+		&Column{Type: Int, Name: "age"}, &Column{Type: BigInt, Name: "total"}, &Column{Type: Decimal, Name: "amount"}))
+	registry.SetDB(db)
+	return stub, newHandler(t, registry)
+}
+
+func TestAggregate_SumType(t *testing.T) {
+	_, h := newAggregateHandler(t, []string{"count", "sum__age", "sum__total", "sum__amount", "avg__amount"},
+		[]driver.Value{int64(2), int64(70), "9007199254740993", "12.30", "6.15"})
+
+	data, errs := execute(t, h, `{ ordersAggregate { count sum { age total amount } avg { amount } } }`)
+	assert.Empty(t, errs)
+	// the BigInt and Decimal sums keep their exact value, the sums of Int are Float.
+	assert.Equal(t, []interface{}{map[string]interface{}{
+		"count": float64(2),
+		"sum":   map[string]interface{}{"age": float64(70), "total": "9007199254740993", "amount": "12.30"},
+		"avg":   map[string]interface{}{"amount": 6.15},
+	}}, data["ordersAggregate"])
+
+	data, errs = execute(t, h, `{
+		sums: __type(name: "OrderAggregateSums") { fields { name type { name } } }
+		having: __type(name: "OrderAggregateSumsFilter") { inputFields { name } }
+	}`)
+	assert.Empty(t, errs)
+	types := make(map[string]interface{})
+	for _, field := range data["sums"].(map[string]interface{})["fields"].([]interface{}) {
+		field := field.(map[string]interface{})
+		types[field["name"].(string)] = field["type"].(map[string]interface{})["name"]
+	}
+	assert.Equal(t, map[string]interface{}{"id": "Float", "age": "Float", "total": "BigInt", "amount": "Decimal"}, types)
+	assert.Len(t, data["having"].(map[string]interface{})["inputFields"], 4)
+}
+
+func TestAggregate_GroupByHaving(t *testing.T) {
+	stub, h := newAggregateHandler(t, []string{"count", "group__customer", "sum__amount"},
+		[]driver.Value{int64(2), "amy", "12.30"},
+		[]driver.Value{int64(3), "bob", "7"},
+	)
+
+	data, errs := execute(t, h, `{
+		ordersAggregate(
+			filter: { age: { gte: 18 } }
+			groupBy: [customer]
+			having: { count: { gt: 1 }, sum: { amount: { lt: "100" }, age: { gte: 10 } } }
+		) { group { customer age } count sum { amount } }
+	}`)
+	assert.Empty(t, errs)
+	assert.Equal(t, []interface{}{
+		map[string]interface{}{
+			"group": map[string]interface{}{"customer": "amy", "age": nil},
+			"count": float64(2),
+			"sum":   map[string]interface{}{"amount": "12.30"},
+		},
+		map[string]interface{}{
+			"group": map[string]interface{}{"customer": "bob", "age": nil},
+			"count": float64(3),
+			"sum":   map[string]interface{}{"amount": "7"},
+		},
+	}, data["ordersAggregate"])
+	assert.Equal(t, []string{
+		"SELECT COUNT(*) AS count,customer AS group__customer,SUM(amount) AS sum__amount FROM order " +
+			"WHERE  age >= ?  Group By customer HAVING  COUNT(*) > ?  AND  SUM(age) >= ?  AND  SUM(amount) < ?  Order By customer",
+	}, stub.Statements())
+	// the sum of an Int column is compared as a Float.
+	assert.Equal(t, []interface{}{int64(18), int64(1), float64(10), "100"}, stub.args[0])
+}
+
+func TestAggregate_AllRows(t *testing.T) {
+	stub, h := newAggregateHandler(t, []string{"count"}, []driver.Value{int64(5)})
+
+	// without groupBy all rows form a single group.
+	data, errs := execute(t, h, `{ ordersAggregate { count } }`)
+	assert.Empty(t, errs)
+	assert.Equal(t, []interface{}{map[string]interface{}{"count": float64(5)}}, data["ordersAggregate"])
+	assert.Equal(t, []string{"SELECT COUNT(*) AS count FROM order"}, stub.Statements())
+}
+
+func TestHavingCondition(t *testing.T) {
+	RegisterOperator(&Operator{
+		Name:      "havingNoop",
+		InputType: ValueInputType,
+		Builder: func(string, interface{}) (Operation, error) {
+			return NewRawOperation(""), nil
+		},
+	})
+	id := &Column{Type: Int, Name: "id"}
+	id.SetPrimaryKey()
+	n := newTestNode("orders", "order", "order", id, &Column{Type: Int, Name: "age"})
+	registry := core.NewRegistry()
+	registry.Register(n)
+	_ = newHandler(t, registry)
+	d := n.Adapter()
+
+	tests := []struct {
+		name      string
+		having    map[string]interface{}
+		condition string
+		args      []interface{}
+		err       string
+	}{
+		{
+			name:      "count",
+			having:    map[string]interface{}{"count": map[string]interface{}{"gt": 1, "lte": 5}},
+			condition: " COUNT(*) > ?  AND  COUNT(*) <= ? ",
+			args:      []interface{}{1, 5},
+		},
+		{
+			name: "functions in order",
+			having: map[string]interface{}{
+				"max": map[string]interface{}{"age": map[string]interface{}{"lt": 60}},
+				"avg": map[string]interface{}{"age": map[string]interface{}{"gt": 20}},
+			},
+			condition: " AVG(`age`) > ?  AND  MAX(`age`) < ? ",
+			args:      []interface{}{20, 60},
+		},
+		{
+			name:      "empty list",
+			having:    map[string]interface{}{"count": map[string]interface{}{"in": []interface{}{}}},
+			condition: " 1=0 ",
+			args:      []interface{}{},
+		},
+		{
+			name:   "no condition",
+			having: map[string]interface{}{"count": map[string]interface{}{"havingNoop": 1}},
+			err:    "having argument operator havingNoop of COUNT(*) renders no condition",
+		},
+		{
+			name:   "unknown aggregate",
+			having: map[string]interface{}{"median": map[string]interface{}{}},
+			err:    "having argument contains unknown aggregate median",
+		},
+		{
+			name:   "unknown field",
+			having: map[string]interface{}{"sum": map[string]interface{}{"name": map[string]interface{}{"gt": 1}}},
+			err:    "having argument contains unknown field name",
+		},
+		{
+			name:   "unknown operator",
+			having: map[string]interface{}{"count": map[string]interface{}{"near": 1}},
+			err:    "unsupported operation type: near",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			condition, args, err := d.havingCondition(tt.having)
+			if tt.err != "" {
+				assert.EqualError(t, err, tt.err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.condition, condition)
+			assert.Equal(t, tt.args, args)
+		})
+	}
+}
+
+func TestDisableAggregate(t *testing.T) {
+	n := newUserNode()
+	n.Adapter().DisableAggregate()
+	registry := core.NewRegistry()
+	registry.Register(n)
+	h := newHandler(t, registry)
+
+	data, errs := execute(t, h, `{ __type(name: "Query") { fields { name } } }`)
+	assert.Empty(t, errs)
+	names := make([]string, 0)
+	for _, field := range data["__type"].(map[string]interface{})["fields"].([]interface{}) {
+		names = append(names, field.(map[string]interface{})["name"].(string))
+	}
+	assert.Contains(t, names, "users")
+	assert.NotContains(t, names, "usersAggregate")
+
+	data, errs = execute(t, h, `{ __type(name: "UserAggregate") { name } }`)
+	assert.Empty(t, errs)
+	assert.Nil(t, data["__type"])
+}
@@ -0,0 +1,442 @@
+package adapter
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/graphql-go/graphql"
+
+	sqlArgument "github.com/Finovate/go-gql-builder/pkg/adapter/internal/argument"
+)
+
+const (
+	connectionFirstArgument  = "first"
+	connectionAfterArgument  = "after"
+	connectionLastArgument   = "last"
+	connectionBeforeArgument = "before"
+)
+
+const (
+	// DefaultConnectionPageSize is the number of rows of a page when neither first nor last is given.
+	DefaultConnectionPageSize = 20
+	// MaxConnectionPageSize is the largest first or last accepted by a connection.
+	MaxConnectionPageSize = 100
+)
+
+var pageInfoType = graphql.NewObject(graphql.ObjectConfig{
+	Name:        "PageInfo",
+	Description: "Information about the page of a connection",
+	Fields: graphql.Fields{
+		"hasNextPage": &graphql.Field{
+			Type: graphql.NewNonNull(graphql.Boolean),
+		},
+		"hasPreviousPage": &graphql.Field{
+			Type: graphql.NewNonNull(graphql.Boolean),
+		},
+		"startCursor": &graphql.Field{
+			Type: graphql.String,
+		},
+		"endCursor": &graphql.Field{
+			Type: graphql.String,
+		},
+	},
+})
+
+// connection is the configuration of the connection field of a table, see EnableConnection.
+type connection struct {
+	secret []byte
+	// defaultPageSize 未指定 first 与 last 时的每页行数, maxPageSize 为 first 与 last 的上限.
+	defaultPageSize int
+	maxPageSize     int
+}
+
+// cursorPayload is the signed content of a cursor, the values of the sort columns of a row.
+type cursorPayload struct {
+	// Sort identifies the order the cursor was created for, e.g. user:name DESC,id ASC.
+	Sort   string        `json:"s"`
+	Values []interface{} `json:"v"`
+}
+
+// EnableConnection exposes a Relay connection field of the table, e.g.
+// usersConnection(filter: UserFilter, orderBy: [UserOrderBy!], first: Int, after: String, last: Int, before: String).
+// The rows are paginated by keyset over the orderBy columns followed by the primary keys,
+// so the sort columns should not be nullable. The cursors are signed by HMAC-SHA256 with secret,
+// when secret is empty a random one is generated and the cursors are only valid in the current process.
+// A page has DefaultConnectionPageSize rows unless first or last is given, which may not exceed
+// MaxConnectionPageSize, see SetConnectionPageSize.
+func (d *DefaultSqlAdapter) EnableConnection(secret []byte) {
+	if len(secret) == 0 {
+		secret = make([]byte, 32)
+		_, _ = rand.Read(secret)
+	}
+	d.connection = &connection{
+		secret:          secret,
+		defaultPageSize: DefaultConnectionPageSize,
+		maxPageSize:     MaxConnectionPageSize,
+	}
+}
+
+// SetConnectionPageSize changes the default and the maximum page size of the connection field,
+// it should be called after EnableConnection.
+func (d *DefaultSqlAdapter) SetConnectionPageSize(defaultSize int, maxSize int) {
+	if d.connection == nil {
+		return
+	}
+	d.connection.defaultPageSize = defaultSize
+	d.connection.maxPageSize = maxSize
+}
+
+// BuildQueries implements core.QueryBuilder, it generates the xAggregate field of the table unless it is disabled,
+// the lookup fields returning a single row, see buildLookups, and the xConnection field when it is enabled.
+func (d *DefaultSqlAdapter) BuildQueries() (graphql.Fields, error) {
+	if _, err := d.discriminatorColumn(); err != nil {
+		return nil, err
+	}
+	fields, err := d.buildLookups()
+	if err != nil {
+		return nil, err
+	}
+	if !d.aggregateDisabled {
+		aggregate, err := d.buildAggregate()
+		if err != nil {
+			return nil, err
+		}
+		fields[d.node.Name()+"Aggregate"] = aggregate
+	}
+
+	if d.connection != nil {
+		field, err := d.buildConnection()
+		if err != nil {
+			return nil, err
+		}
+		fields[d.node.Name()+"Connection"] = field
+	}
+	return fields, nil
+}
+
+func (d *DefaultSqlAdapter) buildConnection() (*graphql.Field, error) {
+	if len(d.primaryKeys) == 0 {
+		return nil, fmt.Errorf("the connection of %s requires a primary key", d.node.Name())
+	}
+
+	registry := d.node.GetRegistry()
+	obj, err := registry.GetOutput(d.node.Type())
+	if err != nil {
+		return nil, err
+	}
+
+	name := typeName(d.node.Type())
+	edgeType := graphql.NewObject(graphql.ObjectConfig{
+		Name: name + "Edge",
+		Fields: graphql.Fields{
+			"cursor": &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+			"node":   &graphql.Field{Type: graphql.NewNonNull(obj)},
+		},
+	})
+	connectionType := graphql.NewObject(graphql.ObjectConfig{
+		Name: name + "Connection",
+		Fields: graphql.Fields{
+			"edges":      &graphql.Field{Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(edgeType)))},
+			"pageInfo":   &graphql.Field{Type: graphql.NewNonNull(pageInfoType)},
+			"totalCount": &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+		},
+	})
+
+	args := graphql.FieldConfigArgument{
+		connectionFirstArgument:  &graphql.ArgumentConfig{Type: graphql.Int, Description: "returns the first n rows after the cursor"},
+		connectionAfterArgument:  &graphql.ArgumentConfig{Type: graphql.String},
+		connectionLastArgument:   &graphql.ArgumentConfig{Type: graphql.Int, Description: "returns the last n rows before the cursor"},
+		connectionBeforeArgument: &graphql.ArgumentConfig{Type: graphql.String},
+	}
+	for _, argName := range []string{sqlArgument.FilterArgumentType, sqlArgument.OrderByArgumentType} {
+		if arg, ok := registry.GetArgs(d.node.Type())[argName]; ok {
+			args[argName] = arg
+		}
+	}
+
+	return &graphql.Field{
+		Type:    graphql.NewNonNull(connectionType),
+		Args:    args,
+		Resolve: d.resolveConnection,
+	}, nil
+}
+
+func (d *DefaultSqlAdapter) resolveConnection(p graphql.ResolveParams) (interface{}, error) {
+	first, hasFirst := p.Args[connectionFirstArgument].(int)
+	last, hasLast := p.Args[connectionLastArgument].(int)
+	if (hasFirst && first < 0) || (hasLast && last < 0) {
+		return nil, fmt.Errorf("first and last must not be negative")
+	}
+	if max := d.connection.maxPageSize; (hasFirst && first > max) || (hasLast && last > max) {
+		return nil, fmt.Errorf("first and last must not exceed %d", max)
+	}
+	// a connection is always paginated, the first page is read by default.
+	if !hasFirst && !hasLast {
+		first, hasFirst = d.connection.defaultPageSize, true
+	}
+
+	sorts, err := d.connectionSorts(p.Args[sqlArgument.OrderByArgumentType])
+	if err != nil {
+		return nil, err
+	}
+	signature := d.sortSignature(sorts)
+
+	// the sort columns are required by the cursors.
+	required := make([]*Column, len(sorts))
+	for i, s := range sorts {
+		required[i] = d.columnsByAlias[s.Field]
+	}
+	qc := sqlArgument.NewQueryClauses(
+		strings.Join(d.fieldColumns(nestedFields(p, "edges", "node"), required...), ","), d.quote(d.tableName))
+	if err = d.applyFilter(qc, p.Args); err != nil {
+		return nil, err
+	}
+
+	after, hasAfter := p.Args[connectionAfterArgument].(string)
+	if hasAfter {
+		values, err := d.decodeCursor(after, signature, len(sorts))
+		if err != nil {
+			return nil, err
+		}
+		condition, args := keysetCondition(sorts, values, false)
+		qc.AddWhere(condition, args...)
+	}
+	before, hasBefore := p.Args[connectionBeforeArgument].(string)
+	if hasBefore {
+		values, err := d.decodeCursor(before, signature, len(sorts))
+		if err != nil {
+			return nil, err
+		}
+		condition, args := keysetCondition(sorts, values, true)
+		qc.AddWhere(condition, args...)
+	}
+
+	// last without first reads the rows backward from the end, they are reversed afterward.
+	backward := hasLast && !hasFirst
+	qc.SetOrderBy(orderByClause(sorts, backward))
+	// one more row tells whether there is a next page.
+	if hasFirst {
+		qc.SetLimit(d.sqlDialect().Limit(first+1, 0))
+	} else if hasLast {
+		qc.SetLimit(d.sqlDialect().Limit(last+1, 0))
+	}
+
+	rows, err := d.query(p.Context, qc)
+	if err != nil {
+		return nil, err
+	}
+
+	hasNextPage, hasPreviousPage := hasBefore, hasAfter
+	if hasFirst {
+		if len(rows) > first {
+			rows = rows[:first]
+			hasNextPage = true
+		}
+		if hasLast && len(rows) > last {
+			rows = rows[len(rows)-last:]
+			hasPreviousPage = true
+		}
+	} else if hasLast {
+		if len(rows) > last {
+			rows = rows[:last]
+			hasPreviousPage = true
+		}
+		for i, j := 0, len(rows)-1; i < j; i, j = i+1, j-1 {
+			rows[i], rows[j] = rows[j], rows[i]
+		}
+	}
+
+	edges := make([]map[string]interface{}, len(rows))
+	for i, row := range rows {
+		cursor, err := d.encodeCursor(row, sorts, signature)
+		if err != nil {
+			return nil, err
+		}
+		edges[i] = map[string]interface{}{"cursor": cursor, "node": row}
+	}
+	pageInfo := map[string]interface{}{
+		"hasNextPage":     hasNextPage,
+		"hasPreviousPage": hasPreviousPage,
+	}
+	if len(edges) > 0 {
+		pageInfo["startCursor"] = edges[0]["cursor"]
+		pageInfo["endCursor"] = edges[len(edges)-1]["cursor"]
+	}
+
+	result := map[string]interface{}{
+		"edges":    edges,
+		"pageInfo": pageInfo,
+	}
+	for _, field := range collectFields(p) {
+		if field.Name.Value == "totalCount" {
+			if result["totalCount"], err = d.count(p); err != nil {
+				return nil, err
+			}
+			break
+		}
+	}
+	return result, nil
+}
+
+// applyFilter combines the filter argument into the statement, the other arguments are ignored.
+func (d *DefaultSqlAdapter) applyFilter(qc *sqlArgument.QueryClauses, args map[string]interface{}) error {
+	filter, ok := args[sqlArgument.FilterArgumentType]
+	if !ok {
+		return nil
+	}
+	return d.applyArguments(qc, map[string]interface{}{sqlArgument.FilterArgumentType: filter})
+}
+
+// count returns the number of rows matching the filter argument.
+func (d *DefaultSqlAdapter) count(p graphql.ResolveParams) (int, error) {
+	qc := sqlArgument.NewQueryClauses("COUNT(*) AS "+d.quote("total"), d.quote(d.tableName))
+	if err := d.applyFilter(qc, p.Args); err != nil {
+		return 0, err
+	}
+	rows, err := d.query(p.Context, qc)
+	if err != nil || len(rows) == 0 {
+		return 0, err
+	}
+	return strconv.Atoi(fmt.Sprint(rows[0]["total"]))
+}
+
+// connectionSorts returns the sorts of the orderBy argument followed by the primary keys,
+// so the order is total and every row has a distinct cursor.
+func (d *DefaultSqlAdapter) connectionSorts(input interface{}) ([]sqlArgument.Sort, error) {
+	sorts := make([]sqlArgument.Sort, 0, len(d.primaryKeys))
+	if input != nil {
+		orderBy, ok := d.node.GetRegistry().Argument(d.node.Type(), sqlArgument.OrderByArgumentType).(*sqlArgument.OrderByArgument)
+		if !ok {
+			return nil, fmt.Errorf("argument typename is not exist")
+		}
+		orderBy.SetColumnMapper(d)
+		orderBy.SetDialect(d.sqlDialect())
+		if err := orderBy.Validate(input); err != nil {
+			return nil, err
+		}
+		sorts = append(sorts, orderBy.Sorts()...)
+	}
+
+	for _, pk := range d.primaryKeys {
+		sorted := false
+		for _, s := range sorts {
+			if s.Field == pk.Alias {
+				sorted = true
+				break
+			}
+		}
+		if !sorted {
+			sorts = append(sorts, sqlArgument.Sort{Field: pk.Alias, Column: d.quote(pk.Name)})
+		}
+	}
+	return sorts, nil
+}
+
+// sortSignature identifies the order of the rows, a cursor is only accepted by the same order.
+func (d *DefaultSqlAdapter) sortSignature(sorts []sqlArgument.Sort) string {
+	items := make([]string, len(sorts))
+	for i, s := range sorts {
+		items[i] = s.Field + " " + sortDirection(s.Desc, false)
+	}
+	return fmt.Sprintf("%s:%s", d.node.Type(), strings.Join(items, ","))
+}
+
+// encodeCursor encodes the sort values of the row as base64(payload).base64(hmac).
+func (d *DefaultSqlAdapter) encodeCursor(row map[string]interface{}, sorts []sqlArgument.Sort, signature string) (string, error) {
+	values := make([]interface{}, len(sorts))
+	for i, s := range sorts {
+		values[i] = row[s.Field]
+	}
+	payload, err := json.Marshal(cursorPayload{Sort: signature, Values: values})
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(payload) + "." +
+		base64.RawURLEncoding.EncodeToString(d.cursorMac(payload)), nil
+}
+
+// decodeCursor verifies the signature of the cursor and returns its sort values.
+func (d *DefaultSqlAdapter) decodeCursor(cursor string, signature string, size int) ([]interface{}, error) {
+	invalid := fmt.Errorf("invalid cursor: %s", cursor)
+
+	parts := strings.Split(cursor, ".")
+	if len(parts) != 2 {
+		return nil, invalid
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(parts[0])
+	if err != nil {
+		return nil, invalid
+	}
+	mac, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil || !hmac.Equal(mac, d.cursorMac(payload)) {
+		return nil, invalid
+	}
+
+	var content cursorPayload
+	decoder := json.NewDecoder(bytes.NewReader(payload))
+	// keep the numbers as they are, int64 keys do not fit in a float64.
+	decoder.UseNumber()
+	if err = decoder.Decode(&content); err != nil {
+		return nil, invalid
+	}
+	if content.Sort != signature || len(content.Values) != size {
+		return nil, fmt.Errorf("the cursor does not match the orderBy argument")
+	}
+	for i, value := range content.Values {
+		if number, ok := value.(json.Number); ok {
+			content.Values[i] = number.String()
+		}
+	}
+	return content.Values, nil
+}
+
+func (d *DefaultSqlAdapter) cursorMac(payload []byte) []byte {
+	mac := hmac.New(sha256.New, d.connection.secret)
+	mac.Write(payload)
+	return mac.Sum(nil)
+}
+
+// keysetCondition returns the condition selecting the rows after the sort values, or before them,
+// e.g. (a > ?) OR (a = ? AND b > ?) for the ascending sorts a and b.
+func keysetCondition(sorts []sqlArgument.Sort, values []interface{}, before bool) (string, []interface{}) {
+	conditions := make([]string, len(sorts))
+	args := make([]interface{}, 0)
+	for i, s := range sorts {
+		parts := make([]string, 0, i+1)
+		for j := 0; j < i; j++ {
+			parts = append(parts, fmt.Sprintf("%s = ?", sorts[j].Column))
+			args = append(args, values[j])
+		}
+		operator := ">"
+		if s.Desc != before {
+			operator = "<"
+		}
+		parts = append(parts, fmt.Sprintf("%s %s ?", s.Column, operator))
+		args = append(args, values[i])
+		conditions[i] = fmt.Sprintf("(%s)", strings.Join(parts, " AND "))
+	}
+	return strings.Join(conditions, " OR "), args
+}
+
+// orderByClause returns the order of the sorts, or the opposite order when reverse is true.
+func orderByClause(sorts []sqlArgument.Sort, reverse bool) string {
+	items := make([]string, len(sorts))
+	for i, s := range sorts {
+		items[i] = s.Column + " " + sortDirection(s.Desc, reverse)
+	}
+	return strings.Join(items, ",")
+}
+
+func sortDirection(desc bool, reverse bool) string {
+	if desc != reverse {
+		return sqlArgument.SortDirectionDesc
+	}
+	return sqlArgument.SortDirectionAsc
+}
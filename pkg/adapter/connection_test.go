@@ -0,0 +1,178 @@
+package adapter
+
+import (
+	"database/sql/driver"
+	"encoding/base64"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	sqlArgument "github.com/Finovate/go-gql-builder/pkg/adapter/internal/argument"
+	"github.com/Finovate/go-gql-builder/pkg/core"
+)
+
+func newConnectionAdapter() *DefaultSqlAdapter {
+	d := newUserNode().Adapter()
+	d.EnableConnection([]byte("secret"))
+	return d
+}
+
+func TestCursor(t *testing.T) {
+	d := newConnectionAdapter()
+	sorts := []sqlArgument.Sort{{Field: "name", Desc: true}, {Field: "id"}}
+	signature := d.sortSignature(sorts)
+	assert.Equal(t, "user:name DESC,id ASC", signature)
+
+	cursor, err := d.encodeCursor(map[string]interface{}{"id": int64(9007199254740993), "name": "tom"}, sorts, signature)
+	assert.NoError(t, err)
+
+	parts := strings.Split(cursor, ".")
+	payload, _ := base64.RawURLEncoding.DecodeString(parts[0])
+	tampered := base64.RawURLEncoding.EncodeToString([]byte(strings.Replace(string(payload), "tom", "bob", 1))) + "." + parts[1]
+
+	tests := []struct {
+		name      string
+		cursor    string
+		signature string
+		size      int
+		values    []interface{}
+		err       string
+	}{
+		{
+			name:      "valid",
+			cursor:    cursor,
+			signature: signature,
+			size:      2,
+			// the numbers keep their precision.
+			values: []interface{}{"tom", "9007199254740993"},
+		},
+		{
+			name:      "tampered payload",
+			cursor:    tampered,
+			signature: signature,
+			size:      2,
+			err:       "invalid cursor: " + tampered,
+		},
+		{
+			name:      "other secret",
+			cursor:    parts[0] + "." + base64.RawURLEncoding.EncodeToString([]byte("mac")),
+			signature: signature,
+			size:      2,
+			err:       "invalid cursor: " + parts[0] + ".bWFj",
+		},
+		{
+			name:      "malformed",
+			cursor:    "abc",
+			signature: signature,
+			size:      2,
+			err:       "invalid cursor: abc",
+		},
+		{
+			name:      "other order",
+			cursor:    cursor,
+			signature: "user:name ASC,id ASC",
+			size:      2,
+			err:       "the cursor does not match the orderBy argument",
+		},
+		{
+			name:      "other size",
+			cursor:    cursor,
+			signature: signature,
+			size:      3,
+			err:       "the cursor does not match the orderBy argument",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			values, err := d.decodeCursor(tt.cursor, tt.signature, tt.size)
+			if tt.err != "" {
+				assert.EqualError(t, err, tt.err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.values, values)
+		})
+	}
+}
+
+func TestKeysetCondition(t *testing.T) {
+	a := sqlArgument.Sort{Field: "a", Column: "`a`"}
+	b := sqlArgument.Sort{Field: "b", Column: "`b`", Desc: true}
+
+	tests := []struct {
+		name      string
+		sorts     []sqlArgument.Sort
+		before    bool
+		condition string
+		args      []interface{}
+	}{
+		{
+			name:      "after single",
+			sorts:     []sqlArgument.Sort{a},
+			condition: "(`a` > ?)",
+			args:      []interface{}{1},
+		},
+		{
+			name:      "before single",
+			sorts:     []sqlArgument.Sort{a},
+			before:    true,
+			condition: "(`a` < ?)",
+			args:      []interface{}{1},
+		},
+		{
+			name:      "after mixed",
+			sorts:     []sqlArgument.Sort{a, b},
+			condition: "(`a` > ?) OR (`a` = ? AND `b` < ?)",
+			args:      []interface{}{1, 1, 2},
+		},
+		{
+			name:      "before mixed",
+			sorts:     []sqlArgument.Sort{a, b},
+			before:    true,
+			condition: "(`a` < ?) OR (`a` = ? AND `b` > ?)",
+			args:      []interface{}{1, 1, 2},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			condition, args := keysetCondition(tt.sorts, []interface{}{1, 2}[:len(tt.sorts)], tt.before)
+			assert.Equal(t, tt.condition, condition)
+			assert.Equal(t, tt.args, args)
+		})
+	}
+}
+
+func TestOrderByClause(t *testing.T) {
+	sorts := []sqlArgument.Sort{{Field: "a", Column: "`a`"}, {Field: "b", Column: "`b`", Desc: true}}
+	assert.Equal(t, "`a` ASC,`b` DESC", orderByClause(sorts, false))
+	assert.Equal(t, "`a` DESC,`b` ASC", orderByClause(sorts, true))
+	assert.Equal(t, "", orderByClause(nil, false))
+}
+
+func TestResolveConnection_PageSize(t *testing.T) {
+	stub, db := newStubDB(t, func(string, []interface{}) ([]string, [][]driver.Value) {
+		return []string{"id"}, [][]driver.Value{{int64(1)}, {int64(2)}, {int64(3)}}
+	})
+	n := newUserNode()
+	n.Adapter().EnableConnection(nil)
+	n.Adapter().SetConnectionPageSize(2, 10)
+	registry := core.NewRegistry()
+	registry.Register(n)
+	registry.SetDB(db)
+	h := newHandler(t, registry)
+
+	// the default page size applies without first and last.
+	data, errs := execute(t, h, `{ usersConnection { edges { node { id } } pageInfo { hasNextPage } } }`)
+	assert.Empty(t, errs)
+	assert.Equal(t, []string{"SELECT id FROM user Order By id ASC LIMIT 0,3"}, stub.Statements())
+	connection := data["usersConnection"].(map[string]interface{})
+	assert.Len(t, connection["edges"], 2)
+	assert.Equal(t, map[string]interface{}{"hasNextPage": true}, connection["pageInfo"])
+
+	_, errs = execute(t, h, `{ usersConnection(first: 11) { edges { cursor } } }`)
+	assert.Equal(t, []string{"first and last must not exceed 10"}, errs)
+	_, errs = execute(t, h, `{ usersConnection(last: 11) { edges { cursor } } }`)
+	assert.Equal(t, []string{"first and last must not exceed 10"}, errs)
+	assert.Len(t, stub.Statements(), 1)
+}
@@ -0,0 +1,67 @@
+package adapter
+
+import (
+	"fmt"
+
+	"github.com/Finovate/go-gql-builder/pkg/core"
+)
+
+var _ core.PolymorphicNode = (*DefaultSqlAdapter)(nil)
+
+// discriminator maps the values of a column to the Node types of the rows, see SetDiscriminator.
+type discriminator struct {
+	outputType core.FieldType
+	column     string
+	types      map[string]core.FieldType
+}
+
+// SetDiscriminator makes the table hold the rows of several Nodes, e.g. a party table holding persons and companies.
+// The fields of the Node then return outputType, an interface or a union registered with
+// core.NodeRegistry.RegisterInterface or RegisterUnion, and the value of the column picks the type of each row, e.g.
+//
+//	SetDiscriminator("Party", "kind", map[string]core.FieldType{"person": FieldTypePerson, "company": FieldTypeCompany})
+//
+// The table must declare the columns of the fields of every type, a row whose value is not in types has no type
+// and fails to resolve.
+func (d *DefaultSqlAdapter) SetDiscriminator(outputType core.FieldType, column string, types map[string]core.FieldType) {
+	d.discriminator = &discriminator{
+		outputType: outputType,
+		column:     column,
+		types:      types,
+	}
+}
+
+// OutputType returns the interface or union of the rows when the table has a discriminator, see core.PolymorphicNode.
+func (d *DefaultSqlAdapter) OutputType() core.FieldType {
+	if d.discriminator == nil {
+		return ""
+	}
+	return d.discriminator.outputType
+}
+
+// discriminatorColumn returns the declared column of the discriminator, nil when the table has none.
+func (d *DefaultSqlAdapter) discriminatorColumn() (*Column, error) {
+	if d.discriminator == nil {
+		return nil, nil
+	}
+	column, ok := d.columnsByName[d.discriminator.column]
+	if !ok {
+		return nil, fmt.Errorf("%s: unknown discriminator column %s", d.node.Name(), d.discriminator.column)
+	}
+	return column, nil
+}
+
+// setTypename stores the Node type picked by the discriminator in the row, see core.TypenameKey.
+func (d *DefaultSqlAdapter) setTypename(row map[string]interface{}) {
+	column, err := d.discriminatorColumn()
+	if err != nil || column == nil {
+		return
+	}
+	value, ok := row[column.Alias]
+	if !ok || value == nil {
+		return
+	}
+	if t, ok := d.discriminator.types[fmt.Sprint(value)]; ok {
+		row[core.TypenameKey] = t
+	}
+}
@@ -0,0 +1,82 @@
+package adapter
+
+import (
+	"database/sql/driver"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Finovate/go-gql-builder/pkg/core"
+)
+
+// newPartyHandler returns the handler of the table party (id, kind, name, title) holding persons (id, kind, name)
+// and companies (id, kind, title), kind picks the type of the rows of the union Party, every query returns rows.
+func newPartyHandler(t *testing.T, rows ...[]driver.Value) (*stubDB, http.Handler) {
+	stub, db := newStubDB(t, func(string, []interface{}) ([]string, [][]driver.Value) {
+		return []string{"id", "kind", "name", "title"}, rows
+	})
+	columns := func(names ...string) []*Column {
+		id := &Column{Type: Int, Name: "id"}
+		id.SetPrimaryKey()
+		columns := []*Column{id, {Name: "kind"}}
+		for _, name := range names {
+			columns = append(columns, &Column{Name: name})
+		}
+		return columns
+	}
+	parties := newTestNode("parties", "party", "party", columns("name", "title")...)
+	parties.Adapter().SetDiscriminator("Party", "kind", map[string]core.FieldType{"person": "person", "company": "company"})
+
+	registry := core.NewRegistry()
+	registry.Register(newTestNode("persons", "person", "person", columns("name")...))
+	registry.Register(newTestNode("companies", "company", "company", columns("title")...))
+	registry.Register(parties)
+	registry.RegisterUnion(&core.Union{Type: "Party", Types: []core.FieldType{"person", "company"}})
+	registry.SetDB(db)
+	return stub, newHandler(t, registry)
+}
+
+func TestDiscriminator_ResolveType(t *testing.T) {
+	stub, h := newPartyHandler(t,
+		[]driver.Value{int64(1), "person", "tom", nil},
+		[]driver.Value{int64(2), "company", nil, "acme"},
+	)
+
+	data, errs := execute(t, h, `{ parties { __typename ... on persons { name } ... on companies { title } } }`)
+	assert.Empty(t, errs)
+	assert.Equal(t, []interface{}{
+		map[string]interface{}{"__typename": "persons", "name": "tom"},
+		map[string]interface{}{"__typename": "companies", "title": "acme"},
+	}, data["parties"])
+	assert.Equal(t, []string{"SELECT kind,name,title FROM party"}, stub.Statements())
+}
+
+func TestDiscriminator_Fragments(t *testing.T) {
+	stub, h := newPartyHandler(t, []driver.Value{int64(1), "person", "tom", nil})
+
+	// the columns of the fragments on other types are not selected, the discriminator always is.
+	data, errs := execute(t, h, `{ parties { ... on persons { id name } } }`)
+	assert.Empty(t, errs)
+	assert.Equal(t, []interface{}{map[string]interface{}{"id": float64(1), "name": "tom"}}, data["parties"])
+
+	_, errs = execute(t, h, `{ parties { ...company } } fragment company on companies { title }`)
+	assert.Empty(t, errs)
+	assert.Equal(t, []string{
+		"SELECT kind,id,name FROM party",
+		"SELECT kind,title FROM party",
+	}, stub.Statements())
+}
+
+func TestDiscriminator_UnknownValue(t *testing.T) {
+	_, h := newPartyHandler(t,
+		[]driver.Value{int64(1), "person", "tom", nil},
+		[]driver.Value{int64(2), "robot", "r2", nil},
+	)
+
+	// a row of an unknown type fails to resolve instead of being returned as null.
+	data, errs := execute(t, h, `{ parties { ... on persons { name } } }`)
+	assert.Equal(t, []interface{}{map[string]interface{}{"name": "tom"}, nil}, data["parties"])
+	assert.Len(t, errs, 1)
+	assert.Contains(t, errs[0], "Abstract type Party must resolve to an Object type at runtime for field Query.parties")
+}
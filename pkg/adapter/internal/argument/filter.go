@@ -2,13 +2,14 @@ package argument
 
 import (
 	"fmt"
-	"strings"
+	"sort"
 
 	"github.com/graphql-go/graphql"
 	"github.com/graphql-go/graphql/language/ast"
 
 	astCommon "github.com/Finovate/go-gql-builder/pkg/common/ast"
 	"github.com/Finovate/go-gql-builder/pkg/core/argument"
+	"github.com/Finovate/go-gql-builder/pkg/dialect"
 )
 
 var (
@@ -28,66 +29,193 @@ var (
 )
 
 var _ SqlArgument = (*FilterArgument)(nil)
+var _ TypedArgument = (*FilterArgument)(nil)
 
+// maxFilterDepth limits the nesting of _and, _or and _not, so a client can not send an arbitrarily deep filter.
+const maxFilterDepth = 8
+
+// FilterArgument
+// filter 入参, 同一层级的条件之间是 AND 关系, 可以通过 _and, _or, _not 组合嵌套的条件
+// filter:{ status: { equal: "active" }, _or: [{ owner: { equal: "me" } }, { shared: { equal: true } }] }
 type FilterArgument struct {
-	operationsMap map[string][]Operation
+	condition Operation
+	columns   ColumnMapper
+	dialect   dialect.Dialect
 }
 
 func newFilterArgument() argument.Argument {
-	return &FilterArgument{
-		operationsMap: make(map[string][]Operation),
-	}
+	return &FilterArgument{}
 }
 
 func (f *FilterArgument) TypeName() string {
 	return FilterArgumentType
 }
 
+func (f *FilterArgument) SetColumnMapper(mapper ColumnMapper) {
+	f.columns = mapper
+}
+
+func (f *FilterArgument) SetDialect(d dialect.Dialect) {
+	f.dialect = d
+}
+
 func (f *FilterArgument) Validate(input interface{}) error {
+	condition, err := f.parseFilter(input, 0)
+	if err != nil {
+		return err
+	}
+	f.condition = condition
+	return nil
+}
+
+// parseFilter converts a filter object into a LogicalOperation joining all of its conditions with AND,
+// _and, _or and _not are parsed recursively into nested LogicalOperations.
+func (f *FilterArgument) parseFilter(input interface{}, depth int) (Operation, error) {
+	if depth > maxFilterDepth {
+		return nil, fmt.Errorf("filter argument is nested more than %d levels", maxFilterDepth)
+	}
+
 	argsMap, ok := input.(map[string]interface{})
 	if !ok {
-		return fmt.Errorf("filter argument must be a map[string]interface{}")
+		return nil, fmt.Errorf("filter argument must be a map[string]interface{}")
 	}
-	for fieldName, rawMap := range argsMap {
-		operationMap, ok := rawMap.(map[string]interface{})
-		if !ok {
-			return fmt.Errorf("argument for field %s must be a map[string]interface{}", fieldName)
-		}
 
-		for op, value := range operationMap {
-			operation, err := OperationFactory(op, fieldName, value)
+	// iterate the keys in a stable order, so the same filter always produces the same statement.
+	operations := make([]Operation, 0, len(argsMap))
+	for _, key := range SortedKeys(argsMap) {
+		switch key {
+		case LogicalOperatorAnd, LogicalOperatorOr:
+			items, ok := argsMap[key].([]interface{})
+			if !ok {
+				// a single object is accepted as a list with one element.
+				items = []interface{}{argsMap[key]}
+			}
+			children := make([]Operation, 0, len(items))
+			for _, item := range items {
+				child, err := f.parseFilter(item, depth+1)
+				if err != nil {
+					return nil, err
+				}
+				children = append(children, child)
+			}
+			operations = append(operations, newLogicalOperation(key, children...))
+		case LogicalOperatorNot:
+			child, err := f.parseFilter(argsMap[key], depth+1)
 			if err != nil {
-				return err
+				return nil, err
 			}
-			if f.operationsMap[fieldName] == nil {
-				f.operationsMap[fieldName] = make([]Operation, 0)
+			operations = append(operations, newLogicalOperation(key, child))
+		default:
+			fieldOperations, err := f.parseField(key, argsMap[key])
+			if err != nil {
+				return nil, err
 			}
-			f.operationsMap[fieldName] = append(f.operationsMap[fieldName], operation)
+			operations = append(operations, fieldOperations...)
 		}
+	}
+
+	return newLogicalOperation(LogicalOperatorAnd, operations...), nil
+}
+
+// parseField converts the operators applied to a single field, e.g. id: { gt: 1, lt: 10 }.
+func (f *FilterArgument) parseField(fieldName string, rawMap interface{}) ([]Operation, error) {
+	operationMap, ok := rawMap.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("argument for field %s must be a map[string]interface{}", fieldName)
+	}
 
+	columnName := fieldName
+	if f.columns != nil {
+		if columnName, ok = f.columns.ColumnName(fieldName); !ok {
+			return nil, fmt.Errorf("filter argument contains unknown field %s", fieldName)
+		}
 	}
 
-	// TODO validateAndFormat fieldName in Node fields or table columns
-	return nil
+	// the operators receive the quoted column, so they do not need to know the dialect.
+	columnName = sqlDialect(f.dialect).QuoteIdentifier(columnName)
+	operations := make([]Operation, 0, len(operationMap))
+	for _, op := range SortedKeys(operationMap) {
+		operation, err := OperationFactory(op, columnName, operationMap[op])
+		if err != nil {
+			return nil, err
+		}
+		operations = append(operations, operation)
+	}
+	return operations, nil
 }
 
 func (f *FilterArgument) GetArgumentType() graphql.Input {
 	return filterArgumentType
 }
 
-func (f *FilterArgument) ParseSqlValue() string {
-	sqlStrings := make([]string, 0, len(f.operationsMap))
-	for _, opList := range f.operationsMap {
-		for _, operation := range opList {
-			sqlStrings = append(sqlStrings, operation.ToSql())
-		}
+// BuildArgumentType generates an input type with one field per column, each field lists the operators
+// applicable to the column, e.g. UserFilter { id: StringFilter, name: StringFilter, _and: [UserFilter!] }.
+func (f *FilterArgument) BuildArgumentType(typeName string, columns []*ArgumentColumn) graphql.Input {
+	var filterType *graphql.InputObject
+	filterType = graphql.NewInputObject(graphql.InputObjectConfig{
+		Name:        typeName + "Filter",
+		Description: fmt.Sprintf("Filter argument of %s", typeName),
+		// the fields reference the type itself, so they are declared lazily.
+		Fields: graphql.InputObjectConfigFieldMapThunk(func() graphql.InputObjectConfigFieldMap {
+			fields := make(graphql.InputObjectConfigFieldMap, len(columns)+3)
+			for _, column := range columns {
+				fields[column.Name] = &graphql.InputObjectFieldConfig{
+					Type: OperatorInputType(column.Type),
+				}
+			}
+			fields[LogicalOperatorAnd] = &graphql.InputObjectFieldConfig{
+				Type:        graphql.NewList(graphql.NewNonNull(filterType)),
+				Description: "matches when all of the filters match",
+			}
+			fields[LogicalOperatorOr] = &graphql.InputObjectFieldConfig{
+				Type:        graphql.NewList(graphql.NewNonNull(filterType)),
+				Description: "matches when any of the filters matches",
+			}
+			fields[LogicalOperatorNot] = &graphql.InputObjectFieldConfig{
+				Type:        filterType,
+				Description: "matches when the filter does not match",
+			}
+			return fields
+		}),
+	})
+	return filterType
+}
+
+func (f *FilterArgument) ParseSqlValue() (string, []interface{}) {
+	if f.condition == nil {
+		return "", nil
+	}
+	d := sqlDialect(f.dialect)
+	// the top level conditions are not wrapped in parentheses.
+	if logical, ok := f.condition.(*LogicalOperation); ok {
+		return logical.joinSql(d)
 	}
+	return f.condition.ToSql(d)
+}
 
-	return strings.Join(sqlStrings, " AND ")
+// Constant reports whether the filter matches every row or no row whatever the row is,
+// e.g. {} and { _or: [{}] } match every row, { _not: {} } and { id: { in: [] } } match none.
+func (f *FilterArgument) Constant() bool {
+	if f.condition == nil {
+		return true
+	}
+	_, known := constant(f.condition, sqlDialect(f.dialect))
+	return known
 }
 
 func (f *FilterArgument) CombineSql(clauses *QueryClauses) {
-	clauses.SetWhere(f.ParseSqlValue())
+	where, args := f.ParseSqlValue()
+	clauses.SetWhere(where, args...)
+}
+
+// SortedKeys returns the keys of the map in ascending order, so the conditions are generated in a stable order.
+func SortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
 }
 
 func init() {
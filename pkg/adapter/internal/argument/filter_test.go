@@ -0,0 +1,210 @@
+package argument
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Finovate/go-gql-builder/pkg/dialect"
+)
+
+func TestFilterArgument_CombineSql(t *testing.T) {
+	filter := newFilterArgument().(*FilterArgument)
+	err := filter.Validate(map[string]interface{}{
+		"name": map[string]interface{}{OperatorTypeEqual: "tom"},
+		"age":  map[string]interface{}{OperatorTypeIn: []interface{}{18, 19}},
+	})
+	if err != nil {
+		t.Fatalf("Validate failed: %v", err)
+	}
+
+	qc := NewQueryClauses("id", "user")
+	filter.CombineSql(qc)
+	sql, args, err := qc.ToSql()
+	if err != nil {
+		t.Fatalf("ToSql failed: %v", err)
+	}
+
+	assert.Equal(t, "SELECT id FROM user WHERE  `age` IN (?,?)  AND  `name` = ? ", sql)
+	assert.Equal(t, []interface{}{18, 19, "tom"}, args)
+}
+
+type columnMapperMock map[string]string
+
+func (m columnMapperMock) ColumnName(alias string) (string, bool) {
+	name, ok := m[alias]
+	return name, ok
+}
+
+func TestFilterArgument_ColumnMapper(t *testing.T) {
+	mapper := columnMapperMock{"userName": "user_name"}
+
+	filter := newFilterArgument().(*FilterArgument)
+	filter.SetColumnMapper(mapper)
+	err := filter.Validate(map[string]interface{}{
+		"userName": map[string]interface{}{OperatorTypeEqual: "tom"},
+	})
+	if err != nil {
+		t.Fatalf("Validate failed: %v", err)
+	}
+	sql, _ := filter.ParseSqlValue()
+	assert.Equal(t, " `user_name` = ? ", sql)
+
+	// unknown field
+	filter = newFilterArgument().(*FilterArgument)
+	filter.SetColumnMapper(mapper)
+	err = filter.Validate(map[string]interface{}{
+		"password": map[string]interface{}{OperatorTypeEqual: "tom"},
+	})
+	assert.Error(t, err)
+
+	orderBy := newOrderByArgument().(*OrderByArgument)
+	orderBy.SetColumnMapper(mapper)
+	assert.NoError(t, orderBy.Validate(map[string]interface{}{"userName": "desc"}))
+	sql, _ = orderBy.ParseSqlValue()
+	assert.Equal(t, "`user_name` DESC", sql)
+
+	orderBy = newOrderByArgument().(*OrderByArgument)
+	orderBy.SetColumnMapper(mapper)
+	assert.Error(t, orderBy.Validate(map[string]interface{}{"id; DROP TABLE user": "desc"}))
+}
+
+func TestFilterArgument_LogicalOperators(t *testing.T) {
+	filter := newFilterArgument().(*FilterArgument)
+	err := filter.Validate(map[string]interface{}{
+		"status": map[string]interface{}{OperatorTypeEqual: "active"},
+		LogicalOperatorOr: []interface{}{
+			map[string]interface{}{"owner": map[string]interface{}{OperatorTypeEqual: "me"}},
+			map[string]interface{}{
+				LogicalOperatorNot: map[string]interface{}{"age": map[string]interface{}{OperatorTypeGT: 18}},
+			},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Validate failed: %v", err)
+	}
+
+	sql, args := filter.ParseSqlValue()
+	assert.Equal(t, " ( ( `owner` = ? )  OR  ( NOT ( ( `age` > ? ) ) ) )  AND  `status` = ? ", sql)
+	assert.Equal(t, []interface{}{"me", 18, "active"}, args)
+
+	// nesting is limited
+	nested := map[string]interface{}{"status": map[string]interface{}{OperatorTypeEqual: "active"}}
+	for i := 0; i <= maxFilterDepth; i++ {
+		nested = map[string]interface{}{LogicalOperatorNot: nested}
+	}
+	filter = newFilterArgument().(*FilterArgument)
+	assert.Error(t, filter.Validate(nested))
+}
+
+func TestFilterArgument_Dialect(t *testing.T) {
+	filter := newFilterArgument().(*FilterArgument)
+	filter.SetDialect(dialect.PostgreSQL)
+	err := filter.Validate(map[string]interface{}{
+		"name":   map[string]interface{}{OperatorTypeILike: "to%"},
+		"active": map[string]interface{}{OperatorTypeEqual: true},
+	})
+	if err != nil {
+		t.Fatalf("Validate failed: %v", err)
+	}
+
+	limit := newLimitArgument().(*LimitArgument)
+	limit.SetDialect(dialect.PostgreSQL)
+	assert.NoError(t, limit.Validate(map[string]interface{}{"count": 10, "offset": 20}))
+
+	qc := NewQueryClauses("id", "user")
+	filter.CombineSql(qc)
+	limit.CombineSql(qc)
+	sql, args, err := qc.ToSql()
+	if err != nil {
+		t.Fatalf("ToSql failed: %v", err)
+	}
+	assert.Equal(t, `SELECT id FROM user WHERE  "active" = TRUE  AND  "name" ILIKE ?  LIMIT 10 OFFSET 20`, sql)
+	assert.Equal(t, []interface{}{"to%"}, args)
+}
+
+func TestFilterArgument_EmptyGroups(t *testing.T) {
+	cases := []struct {
+		input map[string]interface{}
+		sql   string
+	}{
+		// an empty filter does not restrict the rows.
+		{map[string]interface{}{}, ""},
+		{map[string]interface{}{LogicalOperatorNot: map[string]interface{}{}}, " NOT ( (1=1) ) "},
+		{map[string]interface{}{LogicalOperatorOr: []interface{}{}}, " (1=0) "},
+		{map[string]interface{}{LogicalOperatorAnd: []interface{}{}}, " (1=1) "},
+		{
+			map[string]interface{}{LogicalOperatorOr: []interface{}{
+				map[string]interface{}{},
+				map[string]interface{}{"name": map[string]interface{}{OperatorTypeEqual: "x"}},
+			}},
+			" ( (1=1)  OR  ( `name` = ? ) ) ",
+		},
+	}
+	for i, c := range cases {
+		filter := newFilterArgument().(*FilterArgument)
+		if err := filter.Validate(c.input); err != nil {
+			t.Fatalf("Validate failed for case %d: %v", i, err)
+		}
+		sql, _ := filter.ParseSqlValue()
+		assert.Equalf(t, c.sql, sql, "ParseSqlValue() failed for case %d", i)
+	}
+}
+
+func TestFilterArgument_Constant(t *testing.T) {
+	name := map[string]interface{}{"name": map[string]interface{}{OperatorTypeEqual: "x"}}
+	cases := []struct {
+		input    map[string]interface{}
+		constant bool
+	}{
+		{map[string]interface{}{}, true},
+		{map[string]interface{}{LogicalOperatorNot: map[string]interface{}{}}, true},
+		{map[string]interface{}{LogicalOperatorOr: []interface{}{}}, true},
+		{map[string]interface{}{LogicalOperatorOr: []interface{}{map[string]interface{}{}, name}}, true},
+		{map[string]interface{}{LogicalOperatorAnd: []interface{}{map[string]interface{}{}}}, true},
+		// false AND anything is false.
+		{map[string]interface{}{"name": name["name"], LogicalOperatorOr: []interface{}{}}, true},
+		{name, false},
+		{map[string]interface{}{LogicalOperatorNot: name}, false},
+		{map[string]interface{}{LogicalOperatorAnd: []interface{}{map[string]interface{}{}, name}}, false},
+		{map[string]interface{}{LogicalOperatorOr: []interface{}{map[string]interface{}{LogicalOperatorOr: []interface{}{}}, name}}, false},
+	}
+	for i, c := range cases {
+		filter := newFilterArgument().(*FilterArgument)
+		if err := filter.Validate(c.input); err != nil {
+			t.Fatalf("Validate failed for case %d: %v", i, err)
+		}
+		assert.Equalf(t, c.constant, filter.Constant(), "Constant() failed for case %d", i)
+	}
+}
+
+func TestFilterArgument_EmptyList(t *testing.T) {
+	in := map[string]interface{}{"id": map[string]interface{}{OperatorTypeIn: []interface{}{}}}
+	notIn := map[string]interface{}{"id": map[string]interface{}{OperatorTypeNotIn: []interface{}{}}}
+	name := map[string]interface{}{"name": map[string]interface{}{OperatorTypeEqual: "x"}}
+	cases := []struct {
+		input    map[string]interface{}
+		sql      string
+		constant bool
+	}{
+		// an empty in matches no row, an empty not_in matches every row.
+		{in, " 1=0 ", true},
+		{notIn, " 1=1 ", true},
+		{map[string]interface{}{LogicalOperatorOr: []interface{}{in}}, " ( ( 1=0 ) ) ", true},
+		{map[string]interface{}{LogicalOperatorOr: []interface{}{notIn, name}}, " ( ( 1=1 )  OR  ( `name` = ? ) ) ", true},
+		{map[string]interface{}{LogicalOperatorOr: []interface{}{in, name}}, " ( ( 1=0 )  OR  ( `name` = ? ) ) ", false},
+		{map[string]interface{}{LogicalOperatorNot: in}, " NOT ( ( 1=0 ) ) ", true},
+		{map[string]interface{}{LogicalOperatorNot: notIn}, " NOT ( ( 1=1 ) ) ", true},
+		{map[string]interface{}{"id": map[string]interface{}{OperatorTypeIn: []interface{}{}}, "name": name["name"]},
+			" 1=0  AND  `name` = ? ", true},
+	}
+	for i, c := range cases {
+		filter := newFilterArgument().(*FilterArgument)
+		if err := filter.Validate(c.input); err != nil {
+			t.Fatalf("Validate failed for case %d: %v", i, err)
+		}
+		sql, _ := filter.ParseSqlValue()
+		assert.Equalf(t, c.sql, sql, "ParseSqlValue() failed for case %d", i)
+		assert.Equalf(t, c.constant, filter.Constant(), "Constant() failed for case %d", i)
+	}
+}
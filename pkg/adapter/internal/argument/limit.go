@@ -4,24 +4,24 @@ import (
 	"fmt"
 
 	"github.com/graphql-go/graphql"
-	"github.com/graphql-go/graphql/language/ast"
 
-	astCommon "github.com/Finovate/go-gql-builder/pkg/common/ast"
 	"github.com/Finovate/go-gql-builder/pkg/core/argument"
+	"github.com/Finovate/go-gql-builder/pkg/dialect"
 )
 
 var (
-	limitArgumentType = graphql.NewScalar(graphql.ScalarConfig{
-		Name:        LimitArgumentType,
+	limitArgumentType = graphql.NewInputObject(graphql.InputObjectConfig{
+		Name:        "Limit",
 		Description: "Limit argument",
-		Serialize: func(value interface{}) interface{} {
-			return value
-		},
-		ParseValue: func(value interface{}) interface{} {
-			return value
-		},
-		ParseLiteral: func(valueAST ast.Value) interface{} {
-			return astCommon.ParseAstValue(valueAST)
+		Fields: graphql.InputObjectConfigFieldMap{
+			"count": &graphql.InputObjectFieldConfig{
+				Type:        graphql.NewNonNull(graphql.Int),
+				Description: "maximum number of rows to return",
+			},
+			"offset": &graphql.InputObjectFieldConfig{
+				Type:        graphql.Int,
+				Description: "number of rows to skip",
+			},
 		},
 	})
 )
@@ -32,8 +32,9 @@ var _ SqlArgument = (*LimitArgument)(nil)
 // limit 入参
 // limit:{ count: 10 offset : 0 }
 type LimitArgument struct {
-	limit  int
-	offset int
+	limit   int
+	offset  int
+	dialect dialect.Dialect
 }
 
 func newLimitArgument() argument.Argument {
@@ -47,6 +48,13 @@ func (f *LimitArgument) TypeName() string {
 	return LimitArgumentType
 }
 
+// SetColumnMapper limit does not reference any column.
+func (f *LimitArgument) SetColumnMapper(ColumnMapper) {}
+
+func (f *LimitArgument) SetDialect(d dialect.Dialect) {
+	f.dialect = d
+}
+
 func (f *LimitArgument) Validate(input interface{}) error {
 	argsMap, ok := input.(map[string]interface{})
 	if !ok {
@@ -69,12 +77,14 @@ func (f *LimitArgument) GetArgumentType() graphql.Input {
 	return limitArgumentType
 }
 
-func (f *LimitArgument) ParseSqlValue() string {
-	return fmt.Sprintf("%v,%v", f.offset, f.limit)
+// ParseSqlValue the offset and count are validated integers, so they are written into the statement directly.
+func (f *LimitArgument) ParseSqlValue() (string, []interface{}) {
+	return sqlDialect(f.dialect).Limit(f.limit, f.offset), nil
 }
 
 func (f *LimitArgument) CombineSql(clauses *QueryClauses) {
-	clauses.SetLimit(f.ParseSqlValue())
+	limit, _ := f.ParseSqlValue()
+	clauses.SetLimit(limit)
 }
 
 func init() {
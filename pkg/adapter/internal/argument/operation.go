@@ -5,37 +5,64 @@ import (
 	"log/slog"
 	"reflect"
 	"strings"
+	"time"
+
+	"github.com/Finovate/go-gql-builder/pkg/dialect"
+)
+
+const (
+	OperatorTypeEqual      = "equal"
+	OperatorTypeNotEqual   = "not_equal"
+	OperatorTypeGT         = "gt"  // greater than, >
+	OperatorTypeGTE        = "gte" // greater than or equal, >=
+	OperatorTypeLT         = "lt"  // less than, <
+	OperatorTypeLTE        = "lte" // less than or equal, <=
+	OperatorTypeIn         = "in"
+	OperatorTypeNotIn      = "not_in"
+	OperatorTypeLike       = "like"  // the value is a LIKE pattern
+	OperatorTypeILike      = "ilike" // case-insensitive like
+	OperatorTypeStartsWith = "starts_with"
+	OperatorTypeEndsWith   = "ends_with"
+	OperatorTypeContains   = "contains"
+	OperatorTypeIsNull     = "is_null" // true: IS NULL, false: IS NOT NULL
+	OperatorTypeBetween    = "between" // [low, high]
 )
 
 const (
-	OperatorTypeEqual    = "equal"
-	OperatorTypeNotEqual = "not_equal"
-	OperatorTypeGT       = "gt"  // greater than, >
-	OperatorTypeGTE      = "gte" // greater than or equal, >=
-	OperatorTypeLT       = "lt"  // less than, <
-	OperatorTypeLTE      = "lte" // less than or equal, <=
-	OperatorTypeIn       = "in"
-	OperatorTypeNotIn    = "not_in"
+	LogicalOperatorAnd = "_and"
+	LogicalOperatorOr  = "_or"
+	LogicalOperatorNot = "_not"
 )
 
+// likeEscape is the escape character of the patterns generated by PatternOperation,
+// a backslash is avoided since it is treated differently by MySQL and PostgreSQL string literals.
+const likeEscape = "!"
+
+// Operation is a single condition of a filter argument.
+// ToSql renders it in the dialect as a SQL fragment using "?" placeholders and returns the
+// values to bind to those placeholders, in order, so client input never ends up in the statement text.
+// The placeholders are replaced with the ones of the dialect when the statement is executed.
 type Operation interface {
-	ToSql() string
-	validateAndFormat() error
+	ToSql(d dialect.Dialect) (string, []interface{})
 }
 
+// OperationFactory creates the Operation of a registered operator, see RegisterOperator.
 func OperationFactory(operatorType string, fieldName string, value interface{}) (Operation, error) {
-	switch operatorType {
-	case OperatorTypeEqual, OperatorTypeNotEqual, OperatorTypeGT, OperatorTypeGTE, OperatorTypeLT, OperatorTypeLTE:
-		return newCompareOperation(fieldName, operatorType, value)
-	case OperatorTypeIn, OperatorTypeNotIn:
-		return newContainsOperation(fieldName, operatorType, value)
-	default:
+	operator := getOperator(operatorType)
+	if operator == nil {
 		return nil, fmt.Errorf("unsupported operation type: %s", operatorType)
 	}
+	return operator.Builder(fieldName, value)
 }
 
 var _ Operation = (*CompareOperation)(nil)
 var _ Operation = (*ContainsOperation)(nil)
+var _ Operation = (*LogicalOperation)(nil)
+var _ Operation = (*PatternOperation)(nil)
+var _ Operation = (*NullOperation)(nil)
+var _ Operation = (*BetweenOperation)(nil)
+var _ Operation = (*RawOperation)(nil)
+var _ Operation = (OperationFunc)(nil)
 
 // CompareOperation represents an operation in the SQL statement for comparison.
 type CompareOperation struct {
@@ -56,33 +83,39 @@ func newCompareOperation(fieldName string, operator string, value interface{}) (
 	return op, nil
 }
 
-func (e *CompareOperation) ToSql() string {
-	return fmt.Sprintf(" %s %s '%v' ", e.fieldName, e.getOperator(), e.value)
+func (e *CompareOperation) ToSql(d dialect.Dialect) (string, []interface{}) {
+	if value, ok := e.value.(bool); ok {
+		return fmt.Sprintf(" %s %s %s ", e.fieldName, e.getOperator(), d.BoolLiteral(value)), nil
+	}
+	return fmt.Sprintf(" %s %s ? ", e.fieldName, e.getOperator()), []interface{}{e.value}
 }
 
 func (e *CompareOperation) validateAndFormat() error {
 	if e.value == nil {
 		return fmt.Errorf("CompareOperation.value cannot be nil")
 	}
-	// Based on the function parseAstValue, most data types are converted to String,
-	// except for the Bool type.
-	// Therefore, the validateAndFormat logic here should ensure that the value's data type is either String or Bool.
+	// The typed filter inputs coerce the values to the type of the column,
+	// so the value is expected to be a string, a number or a bool.
 	// Other types should result in an error directly.
+	// dates are bound as they are, e.g. the values of the DateTime and Date scalars.
+	if _, ok := e.value.(time.Time); ok {
+		return nil
+	}
 	value := reflect.ValueOf(e.value)
 	valueType := value.Type()
 
 	switch valueType.Kind() {
-	case reflect.String:
+	case reflect.String,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
 		return nil
 	case reflect.Bool:
-		if value.Bool() {
-			e.value = 1
-		} else {
-			e.value = 0
-		}
+		// bool values are rendered as literals of the dialect.
+		e.value = value.Bool()
 		return nil
 	default:
-		return fmt.Errorf("CompareOperation expects the value to be a string or bool, but got %s ", valueType.String())
+		return fmt.Errorf("CompareOperation expects the value to be a string, number, bool or time, but got %s ", valueType.String())
 	}
 }
 
@@ -143,16 +176,20 @@ func (c *ContainsOperation) validateAndFormat() error {
 	return fmt.Errorf("ContainsOperation expects the value to be an array or slice, but got %s ", valueType.String())
 }
 
-func (c *ContainsOperation) ToSql() string {
-	if c.innerValues == nil || len(c.innerValues) == 0 {
-		return ""
+// ToSql renders an empty list as a constant, IN () matches no row and NOT IN () matches every row.
+func (c *ContainsOperation) ToSql(dialect.Dialect) (string, []interface{}) {
+	if len(c.innerValues) == 0 {
+		if c.operator == OperatorTypeNotIn {
+			return " 1=1 ", nil
+		}
+		return " 1=0 ", nil
 	}
-	values := make([]string, len(c.innerValues))
-	for i, v := range c.innerValues {
-		values[i] = fmt.Sprintf("'%v'", v)
+	placeholders := make([]string, len(c.innerValues))
+	for i := range c.innerValues {
+		placeholders[i] = "?"
 	}
 
-	return fmt.Sprintf(" %s %s (%s) ", c.fieldName, c.getOperator(), strings.Join(values, ","))
+	return fmt.Sprintf(" %s %s (%s) ", c.fieldName, c.getOperator(), strings.Join(placeholders, ",")), c.innerValues
 }
 
 func (c *ContainsOperation) getOperator() string {
@@ -167,3 +204,274 @@ func (c *ContainsOperation) getOperator() string {
 	}
 
 }
+
+// LogicalOperation combines other operations with AND, OR or NOT,
+// the result is wrapped in parentheses so it can be nested safely.
+type LogicalOperation struct {
+	operator   string
+	operations []Operation
+}
+
+func newLogicalOperation(operator string, operations ...Operation) *LogicalOperation {
+	return &LogicalOperation{
+		operator:   operator,
+		operations: operations,
+	}
+}
+
+// ToSql renders an empty group as its identity, an empty AND matches every row and an empty OR matches none,
+// so _not: {} matches no row and _or: [{}, ...] matches every row.
+func (l *LogicalOperation) ToSql(d dialect.Dialect) (string, []interface{}) {
+	sql, args := l.joinSql(d)
+	if sql == "" {
+		sql = l.emptySql()
+	}
+	if l.operator == LogicalOperatorNot {
+		return fmt.Sprintf(" NOT (%s) ", sql), args
+	}
+	return fmt.Sprintf(" (%s) ", sql), args
+}
+
+// joinSql joins the sql of the inner operations without the surrounding parentheses,
+// an operation producing no sql has no condition, it matches every row.
+func (l *LogicalOperation) joinSql(d dialect.Dialect) (string, []interface{}) {
+	sqlStrings := make([]string, 0, len(l.operations))
+	args := make([]interface{}, 0)
+	for _, operation := range l.operations {
+		sqlString, opArgs := operation.ToSql(d)
+		if sqlString == "" {
+			sqlString = " 1=1 "
+		}
+		sqlStrings = append(sqlStrings, sqlString)
+		args = append(args, opArgs...)
+	}
+
+	return strings.Join(sqlStrings, l.getOperator()), args
+}
+
+// constant reports whether the operation matches every row or no row whatever the row is,
+// known is false when the result depends on the rows, e.g. _or: [{}] is always true, _not: {} and in: [] are always false.
+func constant(operation Operation, d dialect.Dialect) (value bool, known bool) {
+	switch o := operation.(type) {
+	case *LogicalOperation:
+	case *ContainsOperation:
+		if len(o.innerValues) == 0 {
+			return o.operator == OperatorTypeNotIn, true
+		}
+		return false, false
+	default:
+		// an operation producing no sql has no condition.
+		if sql, _ := operation.ToSql(d); strings.TrimSpace(sql) == "" {
+			return true, true
+		}
+		return false, false
+	}
+	l := operation.(*LogicalOperation)
+
+	// every operation of a group is joined with AND except the ones of OR, NOT negates the AND of its operations.
+	if l.operator == LogicalOperatorOr {
+		allFalse := true
+		for _, child := range l.operations {
+			v, known := constant(child, d)
+			if known && v {
+				return true, true
+			}
+			allFalse = allFalse && known
+		}
+		return false, allFalse
+	}
+
+	value, known = true, true
+	for _, child := range l.operations {
+		v, childKnown := constant(child, d)
+		if childKnown && !v {
+			value, known = false, true
+			break
+		}
+		known = known && childKnown
+	}
+	if l.operator == LogicalOperatorNot {
+		value = !value
+	}
+	return value, known
+}
+
+// emptySql returns the condition of a group without any operation.
+func (l *LogicalOperation) emptySql() string {
+	if l.operator == LogicalOperatorOr {
+		return "1=0"
+	}
+	return "1=1"
+}
+
+func (l *LogicalOperation) getOperator() string {
+	switch l.operator {
+	case LogicalOperatorOr:
+		return " OR "
+	default:
+		// the operations of NOT are joined with AND as well, NOT (a AND b)
+		return " AND "
+	}
+}
+
+// PatternOperation represents a LIKE condition, the wildcards in the value of
+// starts_with, ends_with and contains are escaped, so they are matched literally.
+type PatternOperation struct {
+	fieldName string
+	operator  string
+	value     interface{}
+	pattern   string
+}
+
+func newPatternOperation(fieldName string, operator string, value interface{}) (*PatternOperation, error) {
+	op := &PatternOperation{
+		fieldName: fieldName,
+		operator:  operator,
+		value:     value,
+	}
+	if err := op.validateAndFormat(); err != nil {
+		return nil, err
+	}
+	return op, nil
+}
+
+func (o *PatternOperation) validateAndFormat() error {
+	value, ok := o.value.(string)
+	if !ok {
+		return fmt.Errorf("PatternOperation expects the value to be a string, but got %T ", o.value)
+	}
+
+	switch o.operator {
+	case OperatorTypeLike, OperatorTypeILike:
+		o.pattern = value
+	case OperatorTypeStartsWith:
+		o.pattern = escapeLike(value) + "%"
+	case OperatorTypeEndsWith:
+		o.pattern = "%" + escapeLike(value)
+	case OperatorTypeContains:
+		o.pattern = "%" + escapeLike(value) + "%"
+	default:
+		return fmt.Errorf("unsupported operator type: %s", o.operator)
+	}
+	return nil
+}
+
+func (o *PatternOperation) ToSql(d dialect.Dialect) (string, []interface{}) {
+	switch o.operator {
+	case OperatorTypeLike:
+		return fmt.Sprintf(" %s LIKE ? ", o.fieldName), []interface{}{o.pattern}
+	case OperatorTypeILike:
+		return fmt.Sprintf(" %s ", d.CaseInsensitiveLike(o.fieldName, "?")), []interface{}{o.pattern}
+	default:
+		return fmt.Sprintf(" %s LIKE ? ESCAPE '%s' ", o.fieldName, likeEscape), []interface{}{o.pattern}
+	}
+}
+
+// escapeLike escapes the LIKE wildcards and the escape character itself.
+func escapeLike(value string) string {
+	return strings.NewReplacer(
+		likeEscape, likeEscape+likeEscape,
+		"%", likeEscape+"%",
+		"_", likeEscape+"_",
+	).Replace(value)
+}
+
+// NullOperation represents an IS NULL or IS NOT NULL condition.
+type NullOperation struct {
+	fieldName string
+	value     interface{}
+	isNull    bool
+}
+
+func newNullOperation(fieldName string, value interface{}) (*NullOperation, error) {
+	op := &NullOperation{
+		fieldName: fieldName,
+		value:     value,
+	}
+	if err := op.validateAndFormat(); err != nil {
+		return nil, err
+	}
+	return op, nil
+}
+
+func (o *NullOperation) validateAndFormat() error {
+	isNull, ok := o.value.(bool)
+	if !ok {
+		return fmt.Errorf("NullOperation expects the value to be a bool, but got %T ", o.value)
+	}
+	o.isNull = isNull
+	return nil
+}
+
+func (o *NullOperation) ToSql(dialect.Dialect) (string, []interface{}) {
+	if o.isNull {
+		return fmt.Sprintf(" %s IS NULL ", o.fieldName), nil
+	}
+	return fmt.Sprintf(" %s IS NOT NULL ", o.fieldName), nil
+}
+
+// BetweenOperation represents a range condition, both bounds are inclusive.
+type BetweenOperation struct {
+	fieldName string
+	value     interface{}
+	low       interface{}
+	high      interface{}
+}
+
+func newBetweenOperation(fieldName string, value interface{}) (*BetweenOperation, error) {
+	op := &BetweenOperation{
+		fieldName: fieldName,
+		value:     value,
+	}
+	if err := op.validateAndFormat(); err != nil {
+		return nil, err
+	}
+	return op, nil
+}
+
+func (o *BetweenOperation) validateAndFormat() error {
+	if o.value == nil {
+		return fmt.Errorf("BetweenOperation.value cannot be nil")
+	}
+
+	value := reflect.ValueOf(o.value)
+	if (value.Kind() != reflect.Slice && value.Kind() != reflect.Array) || value.Len() != 2 {
+		return fmt.Errorf("BetweenOperation expects the value to be a list of two elements, but got %v ", o.value)
+	}
+	o.low = value.Index(0).Interface()
+	o.high = value.Index(1).Interface()
+	if o.low == nil || o.high == nil {
+		return fmt.Errorf("BetweenOperation bounds cannot be nil")
+	}
+	return nil
+}
+
+func (o *BetweenOperation) ToSql(dialect.Dialect) (string, []interface{}) {
+	return fmt.Sprintf(" %s BETWEEN ? AND ? ", o.fieldName), []interface{}{o.low, o.high}
+}
+
+// RawOperation is an Operation made of a SQL fragment and its bind arguments,
+// it is intended for the operators registered outside this package.
+type RawOperation struct {
+	sql  string
+	args []interface{}
+}
+
+func NewRawOperation(sql string, args ...interface{}) *RawOperation {
+	return &RawOperation{
+		sql:  sql,
+		args: args,
+	}
+}
+
+func (o *RawOperation) ToSql(dialect.Dialect) (string, []interface{}) {
+	return o.sql, o.args
+}
+
+// OperationFunc is an Operation rendered by a function, it is intended for the registered operators
+// whose SQL depends on the dialect.
+type OperationFunc func(d dialect.Dialect) (string, []interface{})
+
+func (f OperationFunc) ToSql(d dialect.Dialect) (string, []interface{}) {
+	return f(d)
+}
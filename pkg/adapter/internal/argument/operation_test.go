@@ -1,8 +1,12 @@
 package argument
 
 import (
-	"github.com/stretchr/testify/assert"
 	"testing"
+
+	"github.com/graphql-go/graphql"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Finovate/go-gql-builder/pkg/dialect"
 )
 
 func TestContainsOperation_ValidateAndToSql(t *testing.T) {
@@ -23,12 +27,32 @@ func TestContainsOperation_ValidateAndToSql(t *testing.T) {
 			operator:  OperatorTypeNotIn,
 			value:     []float64{1.1, 2.2, 3.3},
 		},
+		// an empty list matches no row, or every row when it is negated.
+		{
+			fieldName: "age",
+			operator:  OperatorTypeIn,
+			value:     []int{},
+		},
+		{
+			fieldName: "age",
+			operator:  OperatorTypeNotIn,
+			value:     []interface{}{},
+		},
 	}
 
 	exceptionSql := []string{
-		" age IN ('1','2','3') ",
-		" age IN ('a','b','c') ",
-		" age NOT IN ('1.1','2.2','3.3') ",
+		" age IN (?,?,?) ",
+		" age IN (?,?,?) ",
+		" age NOT IN (?,?,?) ",
+		" 1=0 ",
+		" 1=1 ",
+	}
+	exceptionArgs := [][]interface{}{
+		{1, 2, 3},
+		{"a", "b", "c"},
+		{1.1, 2.2, 3.3},
+		nil,
+		nil,
 	}
 
 	for i := 0; i < len(executionCases); i++ {
@@ -39,8 +63,9 @@ func TestContainsOperation_ValidateAndToSql(t *testing.T) {
 			t.Fatalf("Validate failed for case %d: %v", i, err)
 			return
 		}
-		actualSql := curOp.ToSql()
+		actualSql, actualArgs := curOp.ToSql(dialect.MySQL)
 		assert.Equalf(t, exceptionSql[i], actualSql, "ToSql() failed for case %d", i)
+		assert.Equalf(t, exceptionArgs[i], actualArgs, "ToSql() args failed for case %d", i)
 	}
 
 	// error case
@@ -81,8 +106,12 @@ func TestCompareOperation_ValidateAndSql(t *testing.T) {
 	}
 
 	exceptionSql := []string{
-		" age = '18' ",
-		" is_male != '1' ",
+		" age = ? ",
+		" is_male != 1 ",
+	}
+	exceptionArgs := [][]interface{}{
+		{"18"},
+		nil,
 	}
 
 	for i := 0; i < len(executionCases); i++ {
@@ -93,8 +122,9 @@ func TestCompareOperation_ValidateAndSql(t *testing.T) {
 			t.Fatalf("Validate failed for case %d: %v", i, err)
 			return
 		}
-		actualSql := curOp.ToSql()
+		actualSql, actualArgs := curOp.ToSql(dialect.MySQL)
 		assert.Equalf(t, exceptionSql[i], actualSql, "ToSql() failed for case %d", i)
+		assert.Equalf(t, exceptionArgs[i], actualArgs, "ToSql() args failed for case %d", i)
 	}
 
 	// error case
@@ -119,3 +149,115 @@ func TestCompareOperation_ValidateAndSql(t *testing.T) {
 	}
 
 }
+
+func TestCompareOperation_ValueIsNotInterpolated(t *testing.T) {
+	op, err := OperationFactory(OperatorTypeEqual, "name", "' OR '1'='1")
+	if err != nil {
+		t.Fatalf("Validate failed: %v", err)
+	}
+	actualSql, actualArgs := op.ToSql(dialect.MySQL)
+	assert.Equal(t, " name = ? ", actualSql)
+	assert.Equal(t, []interface{}{"' OR '1'='1"}, actualArgs)
+}
+
+func TestPatternOperation_EscapeWildcards(t *testing.T) {
+	executionCases := map[string]string{
+		OperatorTypeLike:       "50%_off",
+		OperatorTypeStartsWith: "50%_off!",
+		OperatorTypeEndsWith:   "50%",
+		OperatorTypeContains:   "a_b",
+	}
+	exceptionArgs := map[string]interface{}{
+		OperatorTypeLike:       "50%_off",
+		OperatorTypeStartsWith: "50!%!_off!!%",
+		OperatorTypeEndsWith:   "%50!%",
+		OperatorTypeContains:   "%a!_b%",
+	}
+
+	for operator, value := range executionCases {
+		op, err := OperationFactory(operator, "title", value)
+		if err != nil {
+			t.Fatalf("Validate failed for %s: %v", operator, err)
+		}
+		_, args := op.ToSql(dialect.MySQL)
+		assert.Equalf(t, []interface{}{exceptionArgs[operator]}, args, "ToSql() args failed for %s", operator)
+	}
+
+	_, err := OperationFactory(OperatorTypeContains, "title", 1)
+	assert.Error(t, err)
+}
+
+func TestNullAndBetweenOperation(t *testing.T) {
+	op, err := OperationFactory(OperatorTypeIsNull, "deleted_at", true)
+	assert.NoError(t, err)
+	sql, args := op.ToSql(dialect.MySQL)
+	assert.Equal(t, " deleted_at IS NULL ", sql)
+	assert.Empty(t, args)
+
+	op, err = OperationFactory(OperatorTypeIsNull, "deleted_at", false)
+	assert.NoError(t, err)
+	sql, _ = op.ToSql(dialect.MySQL)
+	assert.Equal(t, " deleted_at IS NOT NULL ", sql)
+
+	op, err = OperationFactory(OperatorTypeBetween, "age", []interface{}{18, 30})
+	assert.NoError(t, err)
+	sql, args = op.ToSql(dialect.MySQL)
+	assert.Equal(t, " age BETWEEN ? AND ? ", sql)
+	assert.Equal(t, []interface{}{18, 30}, args)
+
+	_, err = OperationFactory(OperatorTypeBetween, "age", []interface{}{18})
+	assert.Error(t, err)
+}
+
+func TestRegisterOperator(t *testing.T) {
+	RegisterOperator(&Operator{
+		Name:      "regexp",
+		InputType: StringInputType,
+		Builder: func(fieldName string, value interface{}) (Operation, error) {
+			return NewRawOperation(fieldName+" REGEXP ?", value), nil
+		},
+	})
+
+	op, err := OperationFactory("regexp", "name", "^to")
+	assert.NoError(t, err)
+	sql, args := op.ToSql(dialect.MySQL)
+	assert.Equal(t, "name REGEXP ?", sql)
+	assert.Equal(t, []interface{}{"^to"}, args)
+
+	_, ok := OperatorInputType(graphql.String).Fields()["regexp"]
+	assert.True(t, ok)
+	_, ok = OperatorInputType(graphql.Int).Fields()["regexp"]
+	assert.False(t, ok)
+}
+
+func TestLogicalOperation_EmptyGroups(t *testing.T) {
+	name, err := OperationFactory(OperatorTypeEqual, "name", "x")
+	if err != nil {
+		t.Fatalf("OperationFactory failed: %v", err)
+	}
+
+	cases := []struct {
+		operation *LogicalOperation
+		sql       string
+		args      []interface{}
+	}{
+		{newLogicalOperation(LogicalOperatorAnd), " (1=1) ", nil},
+		{newLogicalOperation(LogicalOperatorOr), " (1=0) ", nil},
+		{newLogicalOperation(LogicalOperatorNot), " NOT (1=1) ", nil},
+		{newLogicalOperation(LogicalOperatorNot, newLogicalOperation(LogicalOperatorAnd)), " NOT ( (1=1) ) ", []interface{}{}},
+		{
+			newLogicalOperation(LogicalOperatorOr, newLogicalOperation(LogicalOperatorAnd), name),
+			" ( (1=1)  OR  name = ? ) ",
+			[]interface{}{"x"},
+		},
+	}
+	for i, c := range cases {
+		sql, args := c.operation.ToSql(dialect.MySQL)
+		assert.Equalf(t, c.sql, sql, "ToSql() failed for case %d", i)
+		if c.args == nil {
+			assert.Emptyf(t, args, "ToSql() args failed for case %d", i)
+		} else {
+			assert.Equalf(t, c.args, args, "ToSql() args failed for case %d", i)
+		}
+	}
+}
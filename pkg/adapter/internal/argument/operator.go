@@ -0,0 +1,188 @@
+package argument
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/graphql-go/graphql"
+
+	"github.com/Finovate/go-gql-builder/pkg/core"
+)
+
+// OperatorBuilder creates the Operation of an operator for a column and the value supplied by the client.
+// fieldName is already translated into the column name.
+type OperatorBuilder func(fieldName string, value interface{}) (Operation, error)
+
+// Operator declares an operator of the filter argument, e.g. id: { <operator>: <value> }.
+type Operator struct {
+	Name        string
+	Description string
+	// InputType returns the input type of the operator value for a column whose values are of valueType,
+	// nil means the operator is not applicable to such columns and is left out of the filter input.
+	InputType func(valueType graphql.Input) graphql.Input
+	Builder   OperatorBuilder
+}
+
+var (
+	operators     = make(map[string]*Operator)
+	operatorsLock sync.RWMutex
+
+	// operatorTypes caches the operator input types by the type of the column values,
+	// they are shared by every Node, e.g. StringFilter.
+	operatorTypes     = make(map[graphql.Input]*graphql.InputObject)
+	operatorTypesLock sync.Mutex
+)
+
+// RegisterOperator adds an operator to the filter argument of every Node, an operator with the same name is replaced.
+// It should be called before the schema is built, since the filter input types are generated from the registered operators.
+func RegisterOperator(operator *Operator) {
+	operatorsLock.Lock()
+	defer operatorsLock.Unlock()
+	operators[operator.Name] = operator
+
+	// the cached input types do not contain the new operator.
+	operatorTypesLock.Lock()
+	defer operatorTypesLock.Unlock()
+	operatorTypes = make(map[graphql.Input]*graphql.InputObject)
+}
+
+func getOperator(name string) *Operator {
+	operatorsLock.RLock()
+	defer operatorsLock.RUnlock()
+	return operators[name]
+}
+
+// ValueInputType accepts a single value of the column type, e.g. equal.
+// JSON values can not be compared, the operator does not apply to JSON columns.
+func ValueInputType(valueType graphql.Input) graphql.Input {
+	if valueType == core.JSON {
+		return nil
+	}
+	return valueType
+}
+
+// ListInputType accepts a list of values of the column type, e.g. in.
+func ListInputType(valueType graphql.Input) graphql.Input {
+	if valueType == core.JSON {
+		return nil
+	}
+	return graphql.NewList(graphql.NewNonNull(valueType))
+}
+
+// StringInputType accepts a String and only applies to String columns, e.g. like.
+func StringInputType(valueType graphql.Input) graphql.Input {
+	if valueType != graphql.String {
+		return nil
+	}
+	return graphql.String
+}
+
+// BooleanInputType accepts a Boolean and applies to any column, e.g. is_null.
+func BooleanInputType(graphql.Input) graphql.Input {
+	return graphql.Boolean
+}
+
+// OperatorInputType returns the input type listing the operators applicable to values of valueType, e.g. IntFilter.
+func OperatorInputType(valueType graphql.Input) *graphql.InputObject {
+	operatorTypesLock.Lock()
+	defer operatorTypesLock.Unlock()
+
+	if t, ok := operatorTypes[valueType]; ok {
+		return t
+	}
+
+	operatorsLock.RLock()
+	names := make([]string, 0, len(operators))
+	for name := range operators {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	fields := make(graphql.InputObjectConfigFieldMap, len(names))
+	for _, name := range names {
+		operator := operators[name]
+		inputType := operator.InputType(valueType)
+		if inputType == nil {
+			continue
+		}
+		fields[name] = &graphql.InputObjectFieldConfig{
+			Type:        inputType,
+			Description: operator.Description,
+		}
+	}
+	operatorsLock.RUnlock()
+
+	t := graphql.NewInputObject(graphql.InputObjectConfig{
+		Name:        valueType.Name() + "Filter",
+		Description: fmt.Sprintf("Operators applicable to %s values", valueType.Name()),
+		Fields:      fields,
+	})
+	operatorTypes[valueType] = t
+	return t
+}
+
+func compareOperator(operatorType string, description string) *Operator {
+	return &Operator{
+		Name:        operatorType,
+		Description: description,
+		InputType:   ValueInputType,
+		Builder: func(fieldName string, value interface{}) (Operation, error) {
+			return newCompareOperation(fieldName, operatorType, value)
+		},
+	}
+}
+
+func containsOperator(operatorType string, description string) *Operator {
+	return &Operator{
+		Name:        operatorType,
+		Description: description,
+		InputType:   ListInputType,
+		Builder: func(fieldName string, value interface{}) (Operation, error) {
+			return newContainsOperation(fieldName, operatorType, value)
+		},
+	}
+}
+
+func patternOperator(operatorType string, description string) *Operator {
+	return &Operator{
+		Name:        operatorType,
+		Description: description,
+		InputType:   StringInputType,
+		Builder: func(fieldName string, value interface{}) (Operation, error) {
+			return newPatternOperation(fieldName, operatorType, value)
+		},
+	}
+}
+
+func init() {
+	RegisterOperator(compareOperator(OperatorTypeEqual, "equal to"))
+	RegisterOperator(compareOperator(OperatorTypeNotEqual, "not equal to"))
+	RegisterOperator(compareOperator(OperatorTypeGT, "greater than"))
+	RegisterOperator(compareOperator(OperatorTypeGTE, "greater than or equal to"))
+	RegisterOperator(compareOperator(OperatorTypeLT, "less than"))
+	RegisterOperator(compareOperator(OperatorTypeLTE, "less than or equal to"))
+	RegisterOperator(containsOperator(OperatorTypeIn, "in the list"))
+	RegisterOperator(containsOperator(OperatorTypeNotIn, "not in the list"))
+	RegisterOperator(patternOperator(OperatorTypeLike, "matches the LIKE pattern"))
+	RegisterOperator(patternOperator(OperatorTypeILike, "matches the LIKE pattern, ignoring case"))
+	RegisterOperator(patternOperator(OperatorTypeStartsWith, "starts with the value"))
+	RegisterOperator(patternOperator(OperatorTypeEndsWith, "ends with the value"))
+	RegisterOperator(patternOperator(OperatorTypeContains, "contains the value"))
+	RegisterOperator(&Operator{
+		Name:        OperatorTypeIsNull,
+		Description: "true: is null, false: is not null",
+		InputType:   BooleanInputType,
+		Builder: func(fieldName string, value interface{}) (Operation, error) {
+			return newNullOperation(fieldName, value)
+		},
+	})
+	RegisterOperator(&Operator{
+		Name:        OperatorTypeBetween,
+		Description: "between the two values, inclusive",
+		InputType:   ListInputType,
+		Builder: func(fieldName string, value interface{}) (Operation, error) {
+			return newBetweenOperation(fieldName, value)
+		},
+	})
+}
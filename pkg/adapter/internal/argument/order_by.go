@@ -9,6 +9,7 @@ import (
 
 	astCommon "github.com/Finovate/go-gql-builder/pkg/common/ast"
 	"github.com/Finovate/go-gql-builder/pkg/core/argument"
+	"github.com/Finovate/go-gql-builder/pkg/dialect"
 )
 
 var (
@@ -28,21 +29,50 @@ var (
 )
 
 var _ SqlArgument = (*OrderByArgument)(nil)
+var _ TypedArgument = (*OrderByArgument)(nil)
 
-var (
-	sort = map[string]struct{}{
-		"ASC":  {},
-		"DESC": {},
-	}
+const (
+	SortDirectionAsc  = "ASC"
+	SortDirectionDesc = "DESC"
 )
 
+// SortDirection is the type of the columns of the orderBy input objects, e.g. orderBy: [{ name: ASC }].
+var SortDirection = graphql.NewEnum(graphql.EnumConfig{
+	Name:        "SortDirection",
+	Description: "Direction of a sort",
+	Values: graphql.EnumValueConfigMap{
+		SortDirectionAsc:  &graphql.EnumValueConfig{Value: SortDirectionAsc, Description: "ascending"},
+		SortDirectionDesc: &graphql.EnumValueConfig{Value: SortDirectionDesc, Description: "descending"},
+	},
+})
+
+// OrderByArgument
+// orderBy 入参, 列表的顺序即排序的优先级, 单个对象等同于只有一个元素的列表
+// orderBy:[{ name: ASC }, { id: DESC }]
 type OrderByArgument struct {
-	sortMap map[string]string
+	sorts   []*columnSort
+	columns ColumnMapper
+	dialect dialect.Dialect
+}
+
+type columnSort struct {
+	field     string
+	column    string
+	direction string
+}
+
+// Sort is a single sort of the orderBy argument.
+type Sort struct {
+	// Field is the field name supplied by the client.
+	Field string
+	// Column is the quoted column name.
+	Column string
+	Desc   bool
 }
 
 func newOrderByArgument() argument.Argument {
 	return &OrderByArgument{
-		sortMap: make(map[string]string, 0),
+		sorts: make([]*columnSort, 0),
 	}
 }
 
@@ -50,23 +80,56 @@ func (f *OrderByArgument) TypeName() string {
 	return OrderByArgumentType
 }
 
+func (f *OrderByArgument) SetColumnMapper(mapper ColumnMapper) {
+	f.columns = mapper
+}
+
+func (f *OrderByArgument) SetDialect(d dialect.Dialect) {
+	f.dialect = d
+}
+
 func (f *OrderByArgument) Validate(input interface{}) error {
-	argsMap, ok := input.(map[string]interface{})
-	if !ok {
-		return fmt.Errorf("orderBy argument must be a map[string]string{}")
+	var items []interface{}
+	switch value := input.(type) {
+	case map[string]interface{}:
+		items = []interface{}{value}
+	case []interface{}:
+		items = value
+	default:
+		return fmt.Errorf("orderBy argument must be a map[string]string{} or a list of it")
 	}
 
-	for fieldName, rawString := range argsMap {
-		sortString, ok := rawString.(string)
+	for _, item := range items {
+		argsMap, ok := item.(map[string]interface{})
 		if !ok {
-			return fmt.Errorf("argument for field %s must be a string", fieldName)
+			return fmt.Errorf("orderBy argument must be a map[string]string{} or a list of it")
 		}
 
-		_, ok = sort[strings.ToUpper(sortString)]
-		if !ok {
-			return fmt.Errorf(`argument for field %s must be "asc" or "desc"`, fieldName)
+		// fields declared in the same object have no order, sort them to keep the statement stable.
+		for _, fieldName := range SortedKeys(argsMap) {
+			sortString, ok := argsMap[fieldName].(string)
+			if !ok {
+				return fmt.Errorf("argument for field %s must be a string", fieldName)
+			}
+
+			// the untyped orderBy scalar passes the strings as they are, e.g. "desc".
+			direction, ok := SortDirection.ParseValue(strings.ToUpper(sortString)).(string)
+			if !ok {
+				return fmt.Errorf("argument for field %s must be ASC or DESC", fieldName)
+			}
+
+			columnName := fieldName
+			if f.columns != nil {
+				if columnName, ok = f.columns.ColumnName(fieldName); !ok {
+					return fmt.Errorf("orderBy argument contains unknown field %s", fieldName)
+				}
+			}
+			f.sorts = append(f.sorts, &columnSort{
+				field:     fieldName,
+				column:    sqlDialect(f.dialect).QuoteIdentifier(columnName),
+				direction: direction,
+			})
 		}
-		f.sortMap[fieldName] = sortString
 	}
 
 	return nil
@@ -76,17 +139,43 @@ func (f *OrderByArgument) GetArgumentType() graphql.Input {
 	return orderByArgumentType
 }
 
-func (f *OrderByArgument) ParseSqlValue() string {
-	sqlStrings := make([]string, 0, len(f.sortMap))
-	for fieldName, sort := range f.sortMap {
-		sqlStrings = append(sqlStrings, fmt.Sprintf("%s %s", fieldName, sort))
+// BuildArgumentType generates a list of input objects with one field per column, e.g. [UserOrderBy!].
+func (f *OrderByArgument) BuildArgumentType(typeName string, columns []*ArgumentColumn) graphql.Input {
+	fields := make(graphql.InputObjectConfigFieldMap, len(columns))
+	for _, column := range columns {
+		fields[column.Name] = &graphql.InputObjectFieldConfig{
+			Type: SortDirection,
+		}
+	}
+
+	return graphql.NewList(graphql.NewNonNull(graphql.NewInputObject(graphql.InputObjectConfig{
+		Name:        typeName + "OrderBy",
+		Description: fmt.Sprintf("OrderBy argument of %s", typeName),
+		Fields:      fields,
+	})))
+}
+
+// Sorts returns the validated sorts in the order of their priority.
+func (f *OrderByArgument) Sorts() []Sort {
+	sorts := make([]Sort, len(f.sorts))
+	for i, s := range f.sorts {
+		sorts[i] = Sort{Field: s.field, Column: s.column, Desc: s.direction == SortDirectionDesc}
+	}
+	return sorts
+}
+
+func (f *OrderByArgument) ParseSqlValue() (string, []interface{}) {
+	sqlStrings := make([]string, 0, len(f.sorts))
+	for _, s := range f.sorts {
+		sqlStrings = append(sqlStrings, fmt.Sprintf("%s %s", s.column, s.direction))
 	}
 
-	return strings.Join(sqlStrings, ",")
+	return strings.Join(sqlStrings, ","), nil
 }
 
 func (f *OrderByArgument) CombineSql(clauses *QueryClauses) {
-	clauses.SetOrderBy(f.ParseSqlValue())
+	orderBy, _ := f.ParseSqlValue()
+	clauses.SetOrderBy(orderBy)
 }
 
 func init() {
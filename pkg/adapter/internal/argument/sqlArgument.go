@@ -3,12 +3,40 @@ package argument
 import (
 	"fmt"
 
+	"github.com/graphql-go/graphql"
+
 	"github.com/Finovate/go-gql-builder/pkg/core/argument"
+	"github.com/Finovate/go-gql-builder/pkg/dialect"
 )
 
+// ColumnMapper translates a field name supplied by the client into the real table column.
+// It returns false when the field name is not a known column, such names must never reach the statement.
+type ColumnMapper interface {
+	ColumnName(alias string) (string, bool)
+}
+
+// ArgumentColumn describes a column that can be referenced by an argument,
+// Name is the field name exposed to clients and Type is the input type of the column values.
+type ArgumentColumn struct {
+	Name string
+	Type graphql.Input
+}
+
+// TypedArgument is implemented by the arguments whose input type is derived from the columns of a table,
+// so introspection can describe them. typeName is the prefix of the generated types, e.g. UserFilter.
+type TypedArgument interface {
+	BuildArgumentType(typeName string, columns []*ArgumentColumn) graphql.Input
+}
+
 type SqlArgument interface {
 	argument.Argument
-	ParseSqlValue() string
+	// SetColumnMapper must be called before Validate, so the argument can check the field names it receives.
+	SetColumnMapper(mapper ColumnMapper)
+	// SetDialect must be called before Validate as well, the columns are quoted and the SQL rendered in the dialect,
+	// dialect.Default is used when it is not called.
+	SetDialect(d dialect.Dialect)
+	// ParseSqlValue returns the SQL fragment of the argument and the values bound to its placeholders.
+	ParseSqlValue() (string, []interface{})
 	CombineSql(clauses *QueryClauses)
 }
 
@@ -16,7 +44,10 @@ type QueryClauses struct {
 	selectColumn string
 	from         string
 	where        string
+	whereArgs    []interface{}
 	groupBy      string
+	having       string
+	havingArgs   []interface{}
 	orderBy      string
 	limit        string
 }
@@ -33,41 +64,76 @@ func (c *QueryClauses) SetFrom(db string) {
 	c.from = db
 }
 
-func (c *QueryClauses) SetWhere(filter string) {
+// SetWhere sets the where condition, args are bound to the "?" placeholders of filter in order.
+func (c *QueryClauses) SetWhere(filter string, args ...interface{}) {
 	c.where = filter
+	c.whereArgs = args
+}
+
+// AddWhere combines condition with the current where condition using AND.
+func (c *QueryClauses) AddWhere(condition string, args ...interface{}) {
+	if c.where == "" {
+		c.SetWhere(condition, args...)
+		return
+	}
+	c.where = fmt.Sprintf("(%s) AND (%s)", c.where, condition)
+	c.whereArgs = append(c.whereArgs, args...)
 }
 
 func (c *QueryClauses) SetGroupBy(g string) {
 	c.groupBy = g
 }
 
+// SetHaving sets the condition on the groups, args are bound to the "?" placeholders of having in order.
+func (c *QueryClauses) SetHaving(having string, args ...interface{}) {
+	c.having = having
+	c.havingArgs = args
+}
+
 func (c *QueryClauses) SetOrderBy(o string) {
 	c.orderBy = o
 }
 
+// SetLimit sets the limit clause rendered by the dialect, see dialect.Dialect.Limit.
 func (c *QueryClauses) SetLimit(l string) {
 	c.limit = l
 }
 
-func (c *QueryClauses) ToSql() (string, error) {
+// ToSql combines the clauses into a statement with "?" placeholders,
+// the returned args should be passed to the driver along with the statement.
+func (c *QueryClauses) ToSql() (string, []interface{}, error) {
 	sql := ""
+	args := make([]interface{}, 0)
 	if c.selectColumn == "" || c.from == "" {
-		return "", fmt.Errorf("not enough fields combined to form SQL statements")
+		return "", nil, fmt.Errorf("not enough fields combined to form SQL statements")
 	}
 	sql += fmt.Sprintf("SELECT %s FROM %s", c.selectColumn, c.from)
 
 	if c.where != "" {
 		sql += fmt.Sprintf(" WHERE %s", c.where)
+		args = append(args, c.whereArgs...)
 	}
 	if c.groupBy != "" {
 		sql += fmt.Sprintf(" Group By %s", c.groupBy)
 	}
+	if c.having != "" {
+		sql += fmt.Sprintf(" HAVING %s", c.having)
+		args = append(args, c.havingArgs...)
+	}
 	if c.orderBy != "" {
 		sql += fmt.Sprintf(" Order By %s", c.orderBy)
 	}
 	if c.limit != "" {
-		sql += fmt.Sprintf(" LIMIT %s", c.limit)
+		sql += fmt.Sprintf(" %s", c.limit)
 	}
 
-	return sql, nil
+	return sql, args, nil
+}
+
+// sqlDialect returns d, or dialect.Default when d is nil.
+func sqlDialect(d dialect.Dialect) dialect.Dialect {
+	if d == nil {
+		return dialect.Default
+	}
+	return d
 }
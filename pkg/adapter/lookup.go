@@ -0,0 +1,240 @@
+package adapter
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/graphql-go/graphql"
+
+	"github.com/Finovate/go-gql-builder/pkg/core"
+	"github.com/Finovate/go-gql-builder/pkg/core/dataloader"
+)
+
+// AddUniqueKey declares the columns of a unique index of the table, e.g. AddUniqueKey("email") generates
+// the root field userByEmail(email: String!): User, which returns the row of the key or null.
+// The columns are the names in the table, the arguments are named by their aliases.
+func (d *DefaultSqlAdapter) AddUniqueKey(columns ...string) {
+	d.uniqueKeys = append(d.uniqueKeys, columns)
+}
+
+// buildLookups generates the root fields returning a single row, e.g. user(id: ID!) by the primary keys,
+// and userByEmail(email: String!) by every unique key declared with AddUniqueKey.
+func (d *DefaultSqlAdapter) buildLookups() (graphql.Fields, error) {
+	fields := make(graphql.Fields)
+	name := lookupName(typeName(d.node.Type()))
+
+	if len(d.primaryKeys) > 0 {
+		field, err := d.buildLookup(d.primaryKeys, true)
+		if err != nil {
+			return nil, err
+		}
+		fields[name] = field
+	}
+
+	for _, names := range d.uniqueKeys {
+		if len(names) == 0 {
+			return nil, fmt.Errorf("%s: empty unique key", d.node.Name())
+		}
+		columns := make([]*Column, len(names))
+		aliases := make([]string, len(names))
+		for i, columnName := range names {
+			column, ok := d.columnsByName[columnName]
+			if !ok {
+				return nil, fmt.Errorf("%s: unknown unique key column %s", d.node.Name(), columnName)
+			}
+			columns[i] = column
+			aliases[i] = typeName(core.FieldType(column.Alias))
+		}
+		field, err := d.buildLookup(columns, false)
+		if err != nil {
+			return nil, err
+		}
+		fields[name+"By"+strings.Join(aliases, "And")] = field
+	}
+	return fields, nil
+}
+
+// buildLookup returns the field selecting the row by the values of columns, the primary keys without a type are IDs.
+func (d *DefaultSqlAdapter) buildLookup(columns []*Column, primary bool) (*graphql.Field, error) {
+	output, err := d.node.GetRegistry().GetOutput(d.node.Type())
+	if err != nil {
+		return nil, err
+	}
+
+	args := make(graphql.FieldConfigArgument, len(columns))
+	for _, column := range columns {
+		var t graphql.Input = graphql.ID
+		if !primary || column.Type != "" {
+			t = d.inputType(column.Type)
+		}
+		args[column.Alias] = &graphql.ArgumentConfig{Type: graphql.NewNonNull(t)}
+	}
+
+	return &graphql.Field{
+		Type: output,
+		Args: args,
+		Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+			values := make([]interface{}, len(columns))
+			for i, column := range columns {
+				values[i] = p.Args[column.Alias]
+			}
+			key, err := encodeLookupKey(values)
+			if err != nil {
+				return nil, err
+			}
+
+			// the lookups selecting the same columns, e.g. under different aliases, are selected together.
+			name := fmt.Sprintf("%s.%s:%s", d.node.Type(), p.Info.FieldName, strings.Join(d.selectColumns(p, columns...), ","))
+			return dataloader.Load(p.Context, name, key, func(ctx context.Context, keys []interface{}) ([]interface{}, error) {
+				values := make([][]interface{}, len(keys))
+				for i, key := range keys {
+					value, err := decodeLookupKey(key.(string))
+					if err != nil {
+						return nil, err
+					}
+					values[i] = value
+				}
+				return d.fetchByColumns(p, columns, values)
+			}), nil
+		},
+	}, nil
+}
+
+// fetchByColumns selects the rows whose columns hold the values of keys by a single statement,
+// it returns the rows in the order of keys, nil for the keys which do not match any row.
+func (d *DefaultSqlAdapter) fetchByColumns(p graphql.ResolveParams, columns []*Column, keys [][]interface{}) ([]interface{}, error) {
+	qc := d.selectQuery(p, columns...)
+
+	args := make([]interface{}, 0, len(keys)*len(columns))
+	if len(columns) == 1 {
+		for _, key := range keys {
+			if len(key) != 1 {
+				return nil, fmt.Errorf("%s: invalid key %v", d.node.Name(), key)
+			}
+			args = append(args, key[0])
+		}
+		qc.AddWhere(fmt.Sprintf("%s IN (%s)", d.quote(columns[0].Name), placeholders(len(keys))), args...)
+	} else {
+		conditions := make([]string, len(keys))
+		for i, key := range keys {
+			if len(key) != len(columns) {
+				return nil, fmt.Errorf("%s: invalid key %v", d.node.Name(), key)
+			}
+			equals := make([]string, len(columns))
+			for j, column := range columns {
+				equals[j] = fmt.Sprintf("%s = ?", d.quote(column.Name))
+			}
+			conditions[i] = fmt.Sprintf("(%s)", strings.Join(equals, " AND "))
+			args = append(args, key...)
+		}
+		qc.AddWhere(strings.Join(conditions, " OR "), args...)
+	}
+
+	rows, err := d.query(p.Context, qc)
+	if err != nil {
+		return nil, err
+	}
+	values := make([]interface{}, len(keys))
+	// the statement of a single key selects its row, whatever the database compares equal to the key.
+	if len(keys) == 1 {
+		if len(rows) > 0 {
+			values[0] = rows[0]
+		}
+		return values, nil
+	}
+
+	byKey := make(map[string]map[string]interface{}, len(rows))
+	rowKeys := make([]string, len(rows))
+	for i, row := range rows {
+		key := make([]interface{}, len(columns))
+		for j, column := range columns {
+			key[j] = row[column.Alias]
+		}
+		rowKeys[i] = lookupKey(columns, key)
+		byKey[rowKeys[i]] = row
+	}
+
+	lookupKeys := make([]string, len(keys))
+	requested := make(map[string]bool, len(keys))
+	for i, key := range keys {
+		lookupKeys[i] = lookupKey(columns, key)
+		requested[lookupKeys[i]] = true
+	}
+	for i, key := range lookupKeys {
+		if row, ok := byKey[key]; ok {
+			values[i] = row
+			continue
+		}
+		// the columns of a case-insensitive collation match the keys in another case, a row is only
+		// given to such a key when no requested key matches it exactly and no other row matches the key.
+		candidate := -1
+		for j, rowKey := range rowKeys {
+			if requested[rowKey] || !strings.EqualFold(rowKey, key) {
+				continue
+			}
+			if candidate >= 0 {
+				candidate = -1
+				break
+			}
+			candidate = j
+		}
+		if candidate >= 0 {
+			values[i] = rows[candidate]
+		}
+	}
+	return values, nil
+}
+
+// lookupKey returns the string compared by fetchByColumns of the column values, they are converted into
+// the Go type of the columns first, so e.g. the time.Time of a row matches the RFC3339 text of an argument.
+func lookupKey(columns []*Column, values []interface{}) string {
+	key := make([]interface{}, len(columns))
+	for i, column := range columns {
+		value, err := column.Type.convert(values[i])
+		if err != nil {
+			value = values[i]
+		}
+		if t, ok := value.(time.Time); ok {
+			value = t.UTC().Format(time.RFC3339Nano)
+		}
+		key[i] = value
+	}
+	return keyString(key)
+}
+
+// encodeLookupKey encodes the argument values into a comparable dataloader key.
+func encodeLookupKey(values []interface{}) (string, error) {
+	key, err := json.Marshal(values)
+	if err != nil {
+		return "", err
+	}
+	return string(key), nil
+}
+
+// decodeLookupKey decodes a key of encodeLookupKey, the numbers are decoded as strings, see keyString.
+func decodeLookupKey(key string) ([]interface{}, error) {
+	decoder := json.NewDecoder(bytes.NewReader([]byte(key)))
+	decoder.UseNumber()
+	var values []interface{}
+	if err := decoder.Decode(&values); err != nil {
+		return nil, err
+	}
+	for i, value := range values {
+		if number, ok := value.(json.Number); ok {
+			values[i] = number.String()
+		}
+	}
+	return values, nil
+}
+
+// lookupName converts a GraphQL type name prefix into the name of the lookup field, e.g. UserGroup -> userGroup.
+func lookupName(name string) string {
+	if name == "" {
+		return name
+	}
+	return strings.ToLower(name[:1]) + name[1:]
+}
@@ -0,0 +1,143 @@
+package adapter
+
+import (
+	"database/sql/driver"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Finovate/go-gql-builder/pkg/core"
+)
+
+// newLookupHandler returns the handler of the table event (id Int primary key, email, at DateTime)
+// with the unique keys email and at, every query returns rows.
+func newLookupHandler(t *testing.T, rows ...[]driver.Value) (*stubDB, http.Handler) {
+	stub, db := newStubDB(t, func(string, []interface{}) ([]string, [][]driver.Value) {
+		return []string{"id", "email", "at"}, rows
+	})
+	id := &Column{Type: Int, Name: "id"}
+	id.SetPrimaryKey()
+	n := newTestNode("events", "event", "event", id, &Column{Name: "email"}, &Column{Name: "at", Type: DateTime})
+	n.Adapter().AddUniqueKey("email")
+	n.Adapter().AddUniqueKey("at")
+	registry := core.NewRegistry()
+	registry.Register(n)
+	registry.SetDB(db)
+	return stub, newHandler(t, registry)
+}
+
+func TestLookup_SingleKey(t *testing.T) {
+	at := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	stub, h := newLookupHandler(t, []driver.Value{int64(1), "tom@x.com", at})
+
+	// the row of a single key is returned as it is, e.g. by a case-insensitive collation.
+	data, errs := execute(t, h, `{ eventByEmail(email: "TOM@x.com") { id email } }`)
+	assert.Empty(t, errs)
+	assert.Equal(t, map[string]interface{}{"id": float64(1), "email": "tom@x.com"}, data["eventByEmail"])
+	assert.Equal(t, []string{"SELECT email,id FROM event WHERE email IN (?)"}, stub.Statements())
+	assert.Equal(t, []interface{}{"TOM@x.com"}, stub.args[0])
+}
+
+func TestLookup_SingleKeyNotFound(t *testing.T) {
+	_, h := newLookupHandler(t)
+
+	data, errs := execute(t, h, `{ event(id: 1) { id } }`)
+	assert.Empty(t, errs)
+	assert.Equal(t, map[string]interface{}{"event": nil}, data)
+}
+
+func TestLookup_Batch(t *testing.T) {
+	at := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	stub, h := newLookupHandler(t,
+		[]driver.Value{int64(1), "tom@x.com", at},
+		[]driver.Value{int64(2), "bob@x.com", at.Add(time.Hour)},
+	)
+
+	data, errs := execute(t, h, `{
+		a: eventByEmail(email: "bob@x.com") { id }
+		b: eventByEmail(email: "TOM@X.COM") { id }
+		c: eventByEmail(email: "amy@x.com") { id }
+	}`)
+	assert.Empty(t, errs)
+	assert.Equal(t, map[string]interface{}{
+		"a": map[string]interface{}{"id": float64(2)},
+		"b": map[string]interface{}{"id": float64(1)},
+		"c": nil,
+	}, data)
+
+	// the DateTime arguments match the time of the rows in any time zone.
+	data, errs = execute(t, h, `{
+		a: eventByAt(at: "2024-01-02T05:04:05+02:00") { id }
+		b: eventByAt(at: "2024-01-02T04:04:05Z") { id }
+	}`)
+	assert.Empty(t, errs)
+	assert.Equal(t, map[string]interface{}{
+		"a": map[string]interface{}{"id": float64(1)},
+		"b": map[string]interface{}{"id": float64(2)},
+	}, data)
+	assert.Equal(t, []string{
+		"SELECT email,id FROM event WHERE email IN (?,?,?)",
+		"SELECT at,id FROM event WHERE at IN (?,?)",
+	}, stub.Statements())
+}
+
+func TestLookup_BatchCase(t *testing.T) {
+	at := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	_, h := newLookupHandler(t,
+		[]driver.Value{int64(1), "abc", at},
+		[]driver.Value{int64(2), "ABC", at.Add(time.Hour)},
+		[]driver.Value{int64(3), "xyz", at.Add(2 * time.Hour)},
+	)
+
+	// each key gets the row matching it exactly, Abc matches two rows and gets none of them.
+	data, errs := execute(t, h, `{
+		a: eventByEmail(email: "ABC") { id }
+		b: eventByEmail(email: "abc") { id }
+		c: eventByEmail(email: "Abc") { id }
+	}`)
+	assert.Empty(t, errs)
+	assert.Equal(t, map[string]interface{}{
+		"a": map[string]interface{}{"id": float64(2)},
+		"b": map[string]interface{}{"id": float64(1)},
+		"c": nil,
+	}, data)
+
+	// a row matched exactly by another key is not given to a key in another case.
+	data, errs = execute(t, h, `{
+		a: eventByEmail(email: "xyz") { id }
+		b: eventByEmail(email: "XYZ") { id }
+	}`)
+	assert.Empty(t, errs)
+	assert.Equal(t, map[string]interface{}{
+		"a": map[string]interface{}{"id": float64(3)},
+		"b": nil,
+	}, data)
+}
+
+func TestLookupKey(t *testing.T) {
+	id := &Column{Type: Int, Name: "id"}
+	at := &Column{Type: DateTime, Name: "at"}
+	untyped := &Column{Name: "code"}
+
+	tests := []struct {
+		name    string
+		columns []*Column
+		a, b    []interface{}
+		equal   bool
+	}{
+		{"int and text", []*Column{id}, []interface{}{int64(1)}, []interface{}{"1"}, true},
+		{"different ints", []*Column{id}, []interface{}{int64(1)}, []interface{}{"2"}, false},
+		{"time and text", []*Column{at},
+			[]interface{}{time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)}, []interface{}{"2024-01-02T11:04:05+08:00"}, true},
+		{"untyped", []*Column{untyped}, []interface{}{int64(7)}, []interface{}{"7"}, true},
+		{"composite", []*Column{id, untyped}, []interface{}{int64(1), "a"}, []interface{}{"1", "a"}, true},
+		{"composite different", []*Column{id, untyped}, []interface{}{int64(1), "a"}, []interface{}{"1", "b"}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.equal, lookupKey(tt.columns, tt.a) == lookupKey(tt.columns, tt.b))
+		})
+	}
+}
@@ -0,0 +1,315 @@
+package adapter
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/graphql-go/graphql"
+
+	sqlArgument "github.com/Finovate/go-gql-builder/pkg/adapter/internal/argument"
+	"github.com/Finovate/go-gql-builder/pkg/core"
+	"github.com/Finovate/go-gql-builder/pkg/dialect"
+)
+
+const (
+	// mutationInputArgument the column values of the created row.
+	mutationInputArgument = "input"
+	// mutationSetArgument the column values written to the updated rows.
+	mutationSetArgument = "set"
+)
+
+// BuildMutations implements core.MutationBuilder, it generates the createX, updateX and deleteX fields of the table,
+// e.g. createUser(input: UserCreateInput!), updateUser(id: String, filter: UserFilter, set: UserUpdateInput!)
+// and deleteUser(id: String, filter: UserFilter).
+// The rows are identified by their primary keys, a table without primary key has no mutation.
+func (d *DefaultSqlAdapter) BuildMutations() (graphql.Fields, error) {
+	if len(d.primaryKeys) == 0 {
+		return nil, nil
+	}
+
+	registry := d.node.GetRegistry()
+	obj, err := registry.GetOutput(d.node.Type())
+	if err != nil {
+		return nil, err
+	}
+
+	name := typeName(d.node.Type())
+	createInput := graphql.NewInputObject(graphql.InputObjectConfig{
+		Name:        name + "CreateInput",
+		Description: fmt.Sprintf("Column values of the created %s", name),
+		Fields:      d.columnInputFields(),
+	})
+	updateInput := graphql.NewInputObject(graphql.InputObjectConfig{
+		Name:        name + "UpdateInput",
+		Description: fmt.Sprintf("Column values written to the updated %s", name),
+		Fields:      d.columnInputFields(),
+	})
+
+	// the rows to update or delete are selected by their primary keys or by the filter argument.
+	targetArgs := func() graphql.FieldConfigArgument {
+		args := make(graphql.FieldConfigArgument)
+		for _, pk := range d.primaryKeys {
+			args[pk.Alias] = &graphql.ArgumentConfig{Type: d.inputType(pk.Type)}
+		}
+		if filter, ok := registry.GetArgs(d.node.Type())[sqlArgument.FilterArgumentType]; ok {
+			args[sqlArgument.FilterArgumentType] = filter
+		}
+		return args
+	}
+	updateArgs := targetArgs()
+	updateArgs[mutationSetArgument] = &graphql.ArgumentConfig{Type: graphql.NewNonNull(updateInput)}
+
+	// the list is nullable, so the result of a rolled back mutation can be cleared, see core.NodeRegistry.EnableMutationTransaction.
+	rowsType := graphql.NewList(graphql.NewNonNull(obj))
+	return graphql.Fields{
+		"create" + name: &graphql.Field{
+			Type: obj,
+			Args: graphql.FieldConfigArgument{
+				mutationInputArgument: &graphql.ArgumentConfig{Type: graphql.NewNonNull(createInput)},
+			},
+			Resolve: d.resolveCreate,
+		},
+		"update" + name: &graphql.Field{
+			Type:    rowsType,
+			Args:    updateArgs,
+			Resolve: d.resolveUpdate,
+		},
+		"delete" + name: &graphql.Field{
+			Type:    rowsType,
+			Args:    targetArgs(),
+			Resolve: d.resolveDelete,
+		},
+	}, nil
+}
+
+func (d *DefaultSqlAdapter) columnInputFields() graphql.InputObjectConfigFieldMap {
+	fields := make(graphql.InputObjectConfigFieldMap, len(d.tableColumns))
+	for _, column := range d.tableColumns {
+		fields[column.Alias] = &graphql.InputObjectFieldConfig{Type: d.inputType(column.Type)}
+	}
+	return fields
+}
+
+func (d *DefaultSqlAdapter) resolveCreate(p graphql.ResolveParams) (interface{}, error) {
+	input, _ := p.Args[mutationInputArgument].(map[string]interface{})
+	columns, values := d.writeValues(input)
+	if len(columns) == 0 {
+		return nil, fmt.Errorf("create %s requires at least one column value", typeName(d.node.Type()))
+	}
+
+	// the primary keys of the created row come from the input, or from the auto increment column.
+	key := make(map[string]interface{}, len(d.primaryKeys))
+	for _, pk := range d.primaryKeys {
+		if value, ok := input[pk.Alias]; ok {
+			key[pk.Alias] = value
+		}
+	}
+	if len(key) < len(d.primaryKeys) && len(d.primaryKeys) > 1 {
+		return nil, fmt.Errorf("create %s requires every primary key value", typeName(d.node.Type()))
+	}
+
+	insert := fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s)",
+		d.quote(d.tableName), strings.Join(columns, ","), placeholders(len(values)))
+	if len(key) == len(d.primaryKeys) {
+		if _, err := d.exec(p.Context, insert, values...); err != nil {
+			return nil, err
+		}
+	} else {
+		id, err := d.insertId(p.Context, insert, values...)
+		if err != nil {
+			return nil, err
+		}
+		key[d.primaryKeys[0].Alias] = id
+	}
+
+	rows, err := d.selectByKeys(p, []map[string]interface{}{key})
+	if err != nil || len(rows) == 0 {
+		return nil, err
+	}
+	return rows[0], nil
+}
+
+func (d *DefaultSqlAdapter) resolveUpdate(p graphql.ResolveParams) (interface{}, error) {
+	set, _ := p.Args[mutationSetArgument].(map[string]interface{})
+	columns, values := d.writeValues(set)
+	if len(columns) == 0 {
+		return nil, fmt.Errorf("update %s requires at least one column value", typeName(d.node.Type()))
+	}
+
+	keys, err := d.mutationTargets(p)
+	if err != nil || len(keys) == 0 {
+		return make([]map[string]interface{}, 0), err
+	}
+	// several rows can not take the same primary key.
+	if len(keys) > 1 {
+		for _, pk := range d.primaryKeys {
+			if _, ok := set[pk.Alias]; ok {
+				return nil, fmt.Errorf("update %s can not change the primary key %s of several rows", typeName(d.node.Type()), pk.Alias)
+			}
+		}
+	}
+
+	assignments := make([]string, len(columns))
+	for i, column := range columns {
+		assignments[i] = fmt.Sprintf("%s = ?", column)
+	}
+	condition, args := d.keysCondition(keys)
+	_, err = d.exec(p.Context, fmt.Sprintf("UPDATE %s SET %s WHERE %s",
+		d.quote(d.tableName), strings.Join(assignments, ","), condition), append(values, args...)...)
+	if err != nil {
+		return nil, err
+	}
+
+	// the primary key of a single row may be changed by the update.
+	for _, key := range keys {
+		for _, pk := range d.primaryKeys {
+			if value, ok := set[pk.Alias]; ok {
+				key[pk.Alias] = value
+			}
+		}
+	}
+	return d.selectByKeys(p, keys)
+}
+
+func (d *DefaultSqlAdapter) resolveDelete(p graphql.ResolveParams) (interface{}, error) {
+	keys, err := d.mutationTargets(p)
+	if err != nil || len(keys) == 0 {
+		return make([]map[string]interface{}, 0), err
+	}
+
+	// the deleted rows are selected before they are gone.
+	rows, err := d.selectByKeys(p, keys)
+	if err != nil {
+		return nil, err
+	}
+
+	condition, args := d.keysCondition(keys)
+	if _, err = d.exec(p.Context, fmt.Sprintf("DELETE FROM %s WHERE %s", d.quote(d.tableName), condition), args...); err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
+
+// mutationTargets returns the primary keys of the rows selected by the primary key and filter arguments.
+func (d *DefaultSqlAdapter) mutationTargets(p graphql.ResolveParams) ([]map[string]interface{}, error) {
+	pkColumns := make([]string, len(d.primaryKeys))
+	for i, pk := range d.primaryKeys {
+		pkColumns[i] = pk.selectExpr(d.sqlDialect())
+	}
+	qc := sqlArgument.NewQueryClauses(strings.Join(pkColumns, ","), d.quote(d.tableName))
+
+	hasFilter := false
+	if value, ok := p.Args[sqlArgument.FilterArgumentType]; ok {
+		arg, err := d.argument(sqlArgument.FilterArgumentType, value)
+		if err != nil {
+			return nil, err
+		}
+		filter, ok := arg.(*sqlArgument.FilterArgument)
+		if !ok {
+			return nil, fmt.Errorf("%s: unsupported filter argument", p.Info.FieldName)
+		}
+		// a filter whose result does not depend on the rows, e.g. {}, { _not: {} } or { id: { in: [] } },
+		// does not select the target rows.
+		if where, _ := filter.ParseSqlValue(); strings.TrimSpace(where) != "" && !filter.Constant() {
+			filter.CombineSql(qc)
+			hasFilter = true
+		}
+	}
+
+	hasKey := false
+	for _, pk := range d.primaryKeys {
+		if value, ok := p.Args[pk.Alias]; ok {
+			qc.AddWhere(fmt.Sprintf("%s = ?", d.quote(pk.Name)), value)
+			hasKey = true
+		}
+	}
+
+	// never touch the whole table by accident.
+	if !hasFilter && !hasKey {
+		return nil, fmt.Errorf("%s requires the primary key or a filter on the columns", p.Info.FieldName)
+	}
+	return d.query(p.Context, qc)
+}
+
+// selectByKeys selects the columns of the selection set from the rows identified by the primary keys.
+func (d *DefaultSqlAdapter) selectByKeys(p graphql.ResolveParams, keys []map[string]interface{}) ([]map[string]interface{}, error) {
+	qc := d.selectQuery(p)
+	condition, args := d.keysCondition(keys)
+	qc.SetWhere(condition, args...)
+	return d.query(p.Context, qc)
+}
+
+// keysCondition returns the condition matching the rows identified by the primary keys.
+func (d *DefaultSqlAdapter) keysCondition(keys []map[string]interface{}) (string, []interface{}) {
+	args := make([]interface{}, 0, len(keys)*len(d.primaryKeys))
+	if len(d.primaryKeys) == 1 {
+		pk := d.primaryKeys[0]
+		for _, key := range keys {
+			args = append(args, key[pk.Alias])
+		}
+		return fmt.Sprintf("%s IN (%s)", d.quote(pk.Name), placeholders(len(keys))), args
+	}
+
+	conditions := make([]string, len(keys))
+	for i, key := range keys {
+		columns := make([]string, len(d.primaryKeys))
+		for j, pk := range d.primaryKeys {
+			columns[j] = fmt.Sprintf("%s = ?", d.quote(pk.Name))
+			args = append(args, key[pk.Alias])
+		}
+		conditions[i] = fmt.Sprintf("(%s)", strings.Join(columns, " AND "))
+	}
+	return strings.Join(conditions, " OR "), args
+}
+
+// writeValues translates the input fields into quoted column names and values, in the order of the table columns.
+func (d *DefaultSqlAdapter) writeValues(input map[string]interface{}) ([]string, []interface{}) {
+	columns := make([]string, 0, len(input))
+	values := make([]interface{}, 0, len(input))
+	for _, column := range d.tableColumns {
+		if value, ok := input[column.Alias]; ok {
+			columns = append(columns, d.quote(column.Name))
+			values = append(values, value)
+		}
+	}
+	return columns, values
+}
+
+func (d *DefaultSqlAdapter) exec(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	querier, err := d.node.GetRegistry().Querier(ctx, d.dataSource)
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := d.withTimeout(ctx)
+	defer cancel()
+	result, err := querier.ExecContext(ctx, dialect.Rebind(d.sqlDialect(), query), args...)
+	return result, core.ContextError(ctx, err)
+}
+
+// insertId runs the insert statement and returns the generated id of the single primary key,
+// the dialects without LastInsertId support return it from the statement, see dialect.Dialect.Returning.
+func (d *DefaultSqlAdapter) insertId(ctx context.Context, insert string, args ...interface{}) (interface{}, error) {
+	returning := d.sqlDialect().Returning(d.quote(d.primaryKeys[0].Name))
+	if returning == "" {
+		result, err := d.exec(ctx, insert, args...)
+		if err != nil {
+			return nil, err
+		}
+		return result.LastInsertId()
+	}
+
+	querier, err := d.node.GetRegistry().Querier(ctx, d.dataSource)
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := d.withTimeout(ctx)
+	defer cancel()
+	var id interface{}
+	err = querier.QueryRowContext(ctx, dialect.Rebind(d.sqlDialect(), insert+returning), args...).Scan(&id)
+	if err != nil {
+		return nil, core.ContextError(ctx, err)
+	}
+	return d.primaryKeys[0].Type.convert(id)
+}
@@ -0,0 +1,137 @@
+package adapter
+
+import (
+	"database/sql/driver"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Finovate/go-gql-builder/pkg/core"
+)
+
+func newMutationHandler(t *testing.T) (*stubDB, http.Handler) {
+	stub, db := newStubDB(t, func(query string, args []interface{}) ([]string, [][]driver.Value) {
+		if strings.HasPrefix(query, "SELECT id FROM user") {
+			return []string{"id"}, [][]driver.Value{{int64(1)}, {int64(2)}}
+		}
+		values := make([][]driver.Value, len(args))
+		for i, arg := range args {
+			values[i] = []driver.Value{arg, "tom"}
+		}
+		return []string{"id", "name"}, values
+	})
+	registry := core.NewRegistry()
+	registry.Register(newUserNode())
+	registry.SetDB(db)
+	registry.EnableMutation()
+	return stub, newHandler(t, registry)
+}
+
+func TestResolveCreate(t *testing.T) {
+	stub, h := newMutationHandler(t)
+	stub.lastInsertId = 5
+
+	data, errs := execute(t, h, `mutation { createUser(input: { name: "tom" }) { id name } }`)
+	assert.Empty(t, errs)
+	assert.Equal(t, map[string]interface{}{"id": float64(5), "name": "tom"}, data["createUser"])
+	assert.Equal(t, []string{
+		"INSERT INTO user (name) VALUES (?)",
+		"SELECT id,name FROM user WHERE id IN (?)",
+	}, stub.Statements())
+
+	// nothing to insert
+	_, errs = execute(t, h, `mutation { createUser(input: {}) { id } }`)
+	assert.Equal(t, []string{"create User requires at least one column value"}, errs)
+}
+
+func TestResolveUpdate(t *testing.T) {
+	stub, h := newMutationHandler(t)
+
+	data, errs := execute(t, h, `mutation { updateUser(filter: { name: { equal: "x" } }, set: { name: "tom" }) { id name } }`)
+	assert.Empty(t, errs)
+	assert.Equal(t, []interface{}{
+		map[string]interface{}{"id": float64(1), "name": "tom"},
+		map[string]interface{}{"id": float64(2), "name": "tom"},
+	}, data["updateUser"])
+	assert.Equal(t, []string{
+		"SELECT id FROM user WHERE  name = ? ",
+		"UPDATE user SET name = ? WHERE id IN (?,?)",
+		"SELECT id,name FROM user WHERE id IN (?,?)",
+	}, stub.Statements())
+	assert.Equal(t, []interface{}{"tom", int64(1), int64(2)}, stub.args[1])
+}
+
+func TestResolveDelete(t *testing.T) {
+	stub, h := newMutationHandler(t)
+
+	data, errs := execute(t, h, `mutation { deleteUser(id: 1) { id } }`)
+	assert.Empty(t, errs)
+	assert.Equal(t, []interface{}{
+		map[string]interface{}{"id": float64(1)},
+		map[string]interface{}{"id": float64(2)},
+	}, data["deleteUser"])
+	assert.Equal(t, []string{
+		"SELECT id FROM user WHERE id = ?",
+		"SELECT id FROM user WHERE id IN (?,?)",
+		"DELETE FROM user WHERE id IN (?,?)",
+	}, stub.Statements())
+}
+
+func TestMutationTargets_RequireCondition(t *testing.T) {
+	for _, filter := range []string{
+		``,
+		`filter: {}`,
+		`filter: { _not: {} }`,
+		`filter: { _or: [] }`,
+		`filter: { _or: [{}, { name: { equal: "x" } }] }`,
+		`filter: { id: { in: [] } }`,
+		`filter: { _or: [{ id: { in: [] } }] }`,
+		`filter: { name: { not_in: [] } }`,
+	} {
+		stub, h := newMutationHandler(t)
+		_, errs := execute(t, h, `mutation { updateUser(set: { name: "x" } `+filter+`) { id } }`)
+		assert.Equalf(t, []string{"updateUser requires the primary key or a filter on the columns"}, errs, "update %s", filter)
+
+		args := ""
+		if filter != "" {
+			args = "(" + filter + ")"
+		}
+		_, errs = execute(t, h, `mutation { deleteUser`+args+` { id } }`)
+		assert.Equalf(t, []string{"deleteUser requires the primary key or a filter on the columns"}, errs, "delete %s", filter)
+		assert.Emptyf(t, stub.Statements(), "no statement is run for %s", filter)
+	}
+}
+
+func TestResolveUpdate_PrimaryKey(t *testing.T) {
+	stub, h := newMutationHandler(t)
+
+	// the filter selects two rows, they can not take the same id.
+	_, errs := execute(t, h, `mutation { updateUser(filter: { name: { equal: "x" } }, set: { id: 9 }) { id } }`)
+	assert.Equal(t, []string{"update User can not change the primary key id of several rows"}, errs)
+	assert.Equal(t, []string{"SELECT id FROM user WHERE  name = ? "}, stub.Statements())
+
+	stub, db := newStubDB(t, func(query string, args []interface{}) ([]string, [][]driver.Value) {
+		if query == "SELECT id FROM user WHERE id = ?" {
+			return []string{"id"}, [][]driver.Value{{int64(1)}}
+		}
+		return []string{"id"}, [][]driver.Value{{args[0]}}
+	})
+	registry := core.NewRegistry()
+	registry.Register(newUserNode())
+	registry.SetDB(db)
+	registry.EnableMutation()
+	h = newHandler(t, registry)
+
+	// the row is selected by its new id.
+	data, errs := execute(t, h, `mutation { updateUser(id: 1, set: { id: 9 }) { id } }`)
+	assert.Empty(t, errs)
+	assert.Equal(t, []interface{}{map[string]interface{}{"id": float64(9)}}, data["updateUser"])
+	assert.Equal(t, []string{
+		"SELECT id FROM user WHERE id = ?",
+		"UPDATE user SET id = ? WHERE id IN (?)",
+		"SELECT id FROM user WHERE id IN (?)",
+	}, stub.Statements())
+	assert.Equal(t, []interface{}{int64(9)}, stub.args[2])
+}
@@ -0,0 +1,38 @@
+package adapter
+
+import (
+	"fmt"
+
+	"github.com/graphql-go/graphql"
+
+	"github.com/Finovate/go-gql-builder/pkg/core"
+)
+
+var _ core.NodeFetcher = (*DefaultSqlAdapter)(nil)
+
+// Identifiable implements core.NodeFetcher, the rows are identified by the primary keys.
+func (d *DefaultSqlAdapter) Identifiable() bool {
+	return len(d.primaryKeys) > 0
+}
+
+// NodeKey implements core.NodeFetcher, it returns the primary key values of a row.
+func (d *DefaultSqlAdapter) NodeKey(value interface{}) ([]interface{}, error) {
+	row, ok := value.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("%s: the global ID requires a row, but got %T", d.node.Name(), value)
+	}
+	key := make([]interface{}, len(d.primaryKeys))
+	for i, pk := range d.primaryKeys {
+		v, ok := row[pk.Alias]
+		if !ok || v == nil {
+			return nil, fmt.Errorf("%s: the global ID requires the primary key %s", d.node.Name(), pk.Alias)
+		}
+		key[i] = v
+	}
+	return key, nil
+}
+
+// FetchNodes implements core.NodeFetcher, the rows of all keys are selected by a single statement.
+func (d *DefaultSqlAdapter) FetchNodes(p graphql.ResolveParams, keys [][]interface{}) ([]interface{}, error) {
+	return d.fetchByColumns(p, d.primaryKeys, keys)
+}
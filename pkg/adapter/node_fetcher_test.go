@@ -0,0 +1,44 @@
+package adapter
+
+import (
+	"database/sql/driver"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Finovate/go-gql-builder/pkg/core"
+)
+
+func TestNodeQuery_RawID(t *testing.T) {
+	stub, db := newStubDB(t, func(string, []interface{}) ([]string, [][]driver.Value) {
+		return []string{"id", "name"}, [][]driver.Value{{int64(1), "tom"}}
+	})
+	registry := core.NewRegistry()
+	registry.Register(newUserNode())
+	registry.SetDB(db)
+	registry.EnableNodeQuery()
+	h := newHandler(t, registry)
+
+	globalID, err := core.EncodeGlobalID("user", []interface{}{int64(1)})
+	assert.NoError(t, err)
+
+	data, errs := execute(t, h, `{ users { id rawId name } }`)
+	assert.Empty(t, errs)
+	assert.Equal(t, []interface{}{
+		map[string]interface{}{"id": globalID, "rawId": float64(1), "name": "tom"},
+	}, data["users"])
+
+	// the raw key alone is enough to select the primary key.
+	data, errs = execute(t, h, `{ users(filter: { id: { equal: 1 } }) { rawId } }`)
+	assert.Empty(t, errs)
+	assert.Equal(t, []interface{}{map[string]interface{}{"rawId": float64(1)}}, data["users"])
+
+	data, errs = execute(t, h, `{ node(id: "`+globalID+`") { id ... on users { rawId } } }`)
+	assert.Empty(t, errs)
+	assert.Equal(t, map[string]interface{}{"id": globalID, "rawId": float64(1)}, data["node"])
+	assert.Equal(t, []string{
+		"SELECT id,name FROM user",
+		"SELECT id FROM user WHERE  id = ? ",
+		"SELECT id FROM user WHERE id IN (?)",
+	}, stub.Statements())
+}
@@ -0,0 +1,56 @@
+package adapter
+
+import (
+	sqlArgument "github.com/Finovate/go-gql-builder/pkg/adapter/internal/argument"
+)
+
+// Operation is a single condition of the filter argument, rendered in the SQL dialect as a fragment
+// with "?" placeholders and the values bound to them.
+type Operation = sqlArgument.Operation
+
+// OperationFunc is an Operation rendered by a function, so the SQL can depend on the dialect.
+type OperationFunc = sqlArgument.OperationFunc
+
+// Operator declares an operator of the filter argument, see RegisterOperator.
+type Operator = sqlArgument.Operator
+
+// OperatorBuilder creates the Operation of an Operator for a column and the value supplied by the client.
+type OperatorBuilder = sqlArgument.OperatorBuilder
+
+// RegisterOperator adds an operator to the filter argument of every SQL Node,
+// so dialect specific operators can be supported without changing the framework.
+// The column passed to the Builder is already quoted, e.g.
+//
+//	adapter.RegisterOperator(&adapter.Operator{
+//		Name:      "regexp",
+//		InputType: adapter.StringInputType,
+//		Builder: func(column string, value interface{}) (adapter.Operation, error) {
+//			return adapter.OperationFunc(func(d dialect.Dialect) (string, []interface{}) {
+//				if d == dialect.PostgreSQL {
+//					return column + " ~ ?", []interface{}{value}
+//				}
+//				return column + " REGEXP ?", []interface{}{value}
+//			}), nil
+//		},
+//	})
+//
+// It should be called before the schema is built.
+func RegisterOperator(operator *Operator) {
+	sqlArgument.RegisterOperator(operator)
+}
+
+// NewRawOperation creates an Operation from a SQL fragment and the values bound to its "?" placeholders.
+func NewRawOperation(sql string, args ...interface{}) Operation {
+	return sqlArgument.NewRawOperation(sql, args...)
+}
+
+var (
+	// ValueInputType makes an operator accept a single value of the column type.
+	ValueInputType = sqlArgument.ValueInputType
+	// ListInputType makes an operator accept a list of values of the column type.
+	ListInputType = sqlArgument.ListInputType
+	// StringInputType makes an operator accept a String, only for String columns.
+	StringInputType = sqlArgument.StringInputType
+	// BooleanInputType makes an operator accept a Boolean, for any column.
+	BooleanInputType = sqlArgument.BooleanInputType
+)
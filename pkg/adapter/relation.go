@@ -0,0 +1,280 @@
+package adapter
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/graphql-go/graphql"
+
+	sqlArgument "github.com/Finovate/go-gql-builder/pkg/adapter/internal/argument"
+	"github.com/Finovate/go-gql-builder/pkg/core"
+	"github.com/Finovate/go-gql-builder/pkg/core/dataloader"
+)
+
+type RelationKind int
+
+const (
+	// RelationBelongsTo the local column references the foreign column of a single target row,
+	// e.g. user.department_id -> department.id
+	RelationBelongsTo RelationKind = iota
+	// RelationHasMany the foreign column of many target rows references the local column,
+	// e.g. department.id <- user.department_id
+	RelationHasMany
+	// RelationManyToMany the rows are linked through a JoinTable,
+	// e.g. user.id <- user_role.user_id, user_role.role_id -> role.id
+	RelationManyToMany
+)
+
+// JoinTable is the intermediate table of a many-to-many relation, it is queried on the data source of the target.
+type JoinTable struct {
+	Name string
+	// LocalColumn references the local column of the relation.
+	LocalColumn string
+	// ForeignColumn references the foreign column of the relation.
+	ForeignColumn string
+}
+
+// Relation links the rows of a DefaultSqlAdapter to the rows of another SQL Node,
+// it is exposed as a field of the Node and resolved from the columns of the parent row.
+type Relation struct {
+	kind          RelationKind
+	fieldName     string
+	target        core.FieldType
+	localColumn   string
+	foreignColumn string
+	through       *JoinTable
+
+	owner *DefaultSqlAdapter
+}
+
+// BelongsTo declares a field resolving the single row of target whose foreignColumn equals the localColumn of this table.
+func (d *DefaultSqlAdapter) BelongsTo(fieldName string, target core.FieldType, localColumn, foreignColumn string) *core.Field {
+	return d.addRelation(&Relation{
+		kind:          RelationBelongsTo,
+		fieldName:     fieldName,
+		target:        target,
+		localColumn:   localColumn,
+		foreignColumn: foreignColumn,
+	})
+}
+
+// HasMany declares a field resolving the rows of target whose foreignColumn equals the localColumn of this table,
+// the filter, orderBy and limit arguments of target are applied to them.
+func (d *DefaultSqlAdapter) HasMany(fieldName string, target core.FieldType, localColumn, foreignColumn string) *core.Field {
+	return d.addRelation(&Relation{
+		kind:          RelationHasMany,
+		fieldName:     fieldName,
+		target:        target,
+		localColumn:   localColumn,
+		foreignColumn: foreignColumn,
+	})
+}
+
+// ManyToMany declares a field resolving the rows of target linked to this table through the join table,
+// the filter, orderBy and limit arguments of target are applied to them.
+func (d *DefaultSqlAdapter) ManyToMany(fieldName string, target core.FieldType, localColumn, foreignColumn string, through *JoinTable) *core.Field {
+	return d.addRelation(&Relation{
+		kind:          RelationManyToMany,
+		fieldName:     fieldName,
+		target:        target,
+		localColumn:   localColumn,
+		foreignColumn: foreignColumn,
+		through:       through,
+	})
+}
+
+func (d *DefaultSqlAdapter) addRelation(relation *Relation) *core.Field {
+	relation.owner = d
+	d.relations[relation.fieldName] = relation
+
+	field := core.NewNodeField(relation.fieldName, relation.target)
+	field.SetAsList(relation.kind != RelationBelongsTo)
+	field.SetResolver(relation.resolve)
+	return field
+}
+
+func (r *Relation) resolve(p graphql.ResolveParams) (interface{}, error) {
+	source, ok := p.Source.(map[string]interface{})
+	if !ok {
+		return nil, nil
+	}
+
+	column, ok := r.owner.columnsByName[r.localColumn]
+	if !ok {
+		return nil, fmt.Errorf("relation %s: unknown local column %s", r.fieldName, r.localColumn)
+	}
+	key := source[column.Alias]
+	if key == nil {
+		return nil, nil
+	}
+
+	target, err := r.targetAdapter()
+	if err != nil {
+		return nil, err
+	}
+	if _, ok = target.columnsByName[r.foreignColumn]; !ok {
+		return nil, fmt.Errorf("relation %s: unknown foreign column %s", r.fieldName, r.foreignColumn)
+	}
+
+	// the parents of one level share the same field AST, so their keys are loaded together.
+	name := fmt.Sprintf("%s.%s", r.owner.node.Type(), r.fieldName)
+	if len(p.Info.FieldASTs) > 0 {
+		name = fmt.Sprintf("%s@%p", name, p.Info.FieldASTs[0])
+	}
+	return dataloader.Load(p.Context, name, key, func(ctx context.Context, keys []interface{}) ([]interface{}, error) {
+		return r.load(p, target, keys)
+	}), nil
+}
+
+// load returns the result of the relation for every key, in the order of keys.
+func (r *Relation) load(p graphql.ResolveParams, target *DefaultSqlAdapter, keys []interface{}) ([]interface{}, error) {
+	values := make([]interface{}, len(keys))
+
+	// a limit applies to the rows of each parent, so it can not be shared by a batched query.
+	if _, ok := p.Args[sqlArgument.LimitArgumentType]; ok {
+		for i, key := range keys {
+			rows, err := r.loadOne(p, target, key)
+			if err != nil {
+				return nil, err
+			}
+			values[i] = r.result(rows)
+		}
+		return values, nil
+	}
+
+	var grouped map[string][]map[string]interface{}
+	var err error
+	if r.kind == RelationManyToMany {
+		grouped, err = r.loadThrough(p, target, keys)
+	} else {
+		grouped, err = r.loadDirect(p, target, keys)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	for i, key := range keys {
+		values[i] = r.result(grouped[keyString(key)])
+	}
+	return values, nil
+}
+
+// loadOne queries the related rows of a single key.
+func (r *Relation) loadOne(p graphql.ResolveParams, target *DefaultSqlAdapter, key interface{}) ([]map[string]interface{}, error) {
+	qc, err := target.buildQuery(p)
+	if err != nil {
+		return nil, err
+	}
+	if r.kind == RelationManyToMany {
+		qc.AddWhere(fmt.Sprintf("%s IN (SELECT %s FROM %s WHERE %s = ?)",
+			target.quote(r.foreignColumn), target.quote(r.through.ForeignColumn),
+			target.quote(r.through.Name), target.quote(r.through.LocalColumn)), key)
+	} else {
+		qc.AddWhere(fmt.Sprintf("%s = ?", target.quote(r.foreignColumn)), key)
+	}
+	return target.query(p.Context, qc)
+}
+
+// loadDirect queries the related rows of all keys at once and groups them by the foreign column.
+func (r *Relation) loadDirect(p graphql.ResolveParams, target *DefaultSqlAdapter, keys []interface{}) (map[string][]map[string]interface{}, error) {
+	foreign := target.columnsByName[r.foreignColumn]
+	qc, err := target.buildQuery(p, foreign)
+	if err != nil {
+		return nil, err
+	}
+	qc.AddWhere(fmt.Sprintf("%s IN (%s)", target.quote(r.foreignColumn), placeholders(len(keys))), keys...)
+
+	rows, err := target.query(p.Context, qc)
+	if err != nil {
+		return nil, err
+	}
+
+	grouped := make(map[string][]map[string]interface{})
+	for _, row := range rows {
+		k := keyString(row[foreign.Alias])
+		grouped[k] = append(grouped[k], row)
+	}
+	return grouped, nil
+}
+
+// loadThrough queries the links of all keys from the join table first, then the linked rows at once,
+// the rows are grouped by the keys linked to them.
+func (r *Relation) loadThrough(p graphql.ResolveParams, target *DefaultSqlAdapter, keys []interface{}) (map[string][]map[string]interface{}, error) {
+	linkQuery := sqlArgument.NewQueryClauses(
+		fmt.Sprintf("%s,%s", target.quote(r.through.LocalColumn), target.quote(r.through.ForeignColumn)),
+		target.quote(r.through.Name))
+	linkQuery.SetWhere(fmt.Sprintf("%s IN (%s)", target.quote(r.through.LocalColumn), placeholders(len(keys))), keys...)
+	links, err := target.query(p.Context, linkQuery)
+	if err != nil {
+		return nil, err
+	}
+
+	grouped := make(map[string][]map[string]interface{})
+	if len(links) == 0 {
+		return grouped, nil
+	}
+
+	// foreign key -> local keys linked to it
+	linked := make(map[string][]string)
+	foreignKeys := make([]interface{}, 0, len(links))
+	for _, link := range links {
+		foreignKey := keyString(link[r.through.ForeignColumn])
+		if _, ok := linked[foreignKey]; !ok {
+			foreignKeys = append(foreignKeys, link[r.through.ForeignColumn])
+		}
+		linked[foreignKey] = append(linked[foreignKey], keyString(link[r.through.LocalColumn]))
+	}
+
+	foreign := target.columnsByName[r.foreignColumn]
+	qc, err := target.buildQuery(p, foreign)
+	if err != nil {
+		return nil, err
+	}
+	qc.AddWhere(fmt.Sprintf("%s IN (%s)", target.quote(r.foreignColumn), placeholders(len(foreignKeys))), foreignKeys...)
+
+	rows, err := target.query(p.Context, qc)
+	if err != nil {
+		return nil, err
+	}
+	for _, row := range rows {
+		for _, localKey := range linked[keyString(row[foreign.Alias])] {
+			grouped[localKey] = append(grouped[localKey], row)
+		}
+	}
+	return grouped, nil
+}
+
+func (r *Relation) result(rows []map[string]interface{}) interface{} {
+	if r.kind == RelationBelongsTo {
+		if len(rows) == 0 {
+			return nil
+		}
+		return rows[0]
+	}
+	if rows == nil {
+		return make([]map[string]interface{}, 0)
+	}
+	return rows
+}
+
+func (r *Relation) targetAdapter() (*DefaultSqlAdapter, error) {
+	node, err := r.owner.node.GetRegistry().GetNode(r.target)
+	if err != nil {
+		return nil, err
+	}
+	sqlNode, ok := node.(SqlAdapter)
+	if !ok {
+		return nil, fmt.Errorf("relation %s: node %s is not a SQL node", r.fieldName, r.target)
+	}
+	return sqlNode.Adapter(), nil
+}
+
+// keyString normalizes a key, the same value may be scanned as different types from different queries.
+func keyString(key interface{}) string {
+	return fmt.Sprint(key)
+}
+
+func placeholders(n int) string {
+	return strings.TrimSuffix(strings.Repeat("?,", n), ",")
+}
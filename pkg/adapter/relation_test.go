@@ -0,0 +1,164 @@
+package adapter
+
+import (
+	"database/sql/driver"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Finovate/go-gql-builder/pkg/core"
+)
+
+// newRelationHandler returns the handler of the tables department (id), user (id, name, department_id),
+// role (id, name) and user_role (user_id, role_id), whose Nodes are related by
+// user.department, department.users and user.roles.
+func newRelationHandler(t *testing.T) (*stubDB, http.Handler) {
+	stub, db := newStubDB(t, func(query string, _ []interface{}) ([]string, [][]driver.Value) {
+		switch {
+		case strings.Contains(query, "FROM user_role"):
+			return []string{"user_id", "role_id"}, [][]driver.Value{
+				{int64(1), int64(100)}, {int64(1), int64(101)}, {int64(2), int64(100)},
+			}
+		case strings.Contains(query, "FROM role"):
+			return []string{"id", "name"}, [][]driver.Value{{int64(100), "admin"}, {int64(101), "dev"}}
+		case strings.Contains(query, "FROM department"):
+			return []string{"id"}, [][]driver.Value{{int64(10)}, {int64(20)}}
+		}
+		return []string{"id", "name", "department_id"}, [][]driver.Value{
+			{int64(1), "tom", int64(10)}, {int64(2), "bob", int64(10)}, {int64(3), "amy", int64(20)},
+		}
+	})
+
+	departmentID := &Column{Type: Int, Name: "id"}
+	departmentID.SetPrimaryKey()
+	department := newTestNode("departments", "department", "department", departmentID)
+	department.fields = append(department.fields, department.Adapter().HasMany("users", "user", "id", "department_id"))
+
+	userID := &Column{Type: Int, Name: "id"}
+	userID.SetPrimaryKey()
+	user := newTestNode("users", "user", "user", userID, &Column{Name: "name"}, &Column{Type: Int, Name: "department_id"})
+	user.fields = append(user.fields,
+		user.Adapter().BelongsTo("department", "department", "department_id", "id"),
+		user.Adapter().ManyToMany("roles", "role", "id", "id", &JoinTable{Name: "user_role", LocalColumn: "user_id", ForeignColumn: "role_id"}),
+	)
+
+	roleID := &Column{Type: Int, Name: "id"}
+	roleID.SetPrimaryKey()
+	role := newTestNode("roles", "role", "role", roleID, &Column{Name: "name"})
+
+	registry := core.NewRegistry()
+	registry.Register(department)
+	registry.Register(user)
+	registry.Register(role)
+	registry.SetDB(db)
+	return stub, newHandler(t, registry)
+}
+
+func TestRelation_BelongsTo(t *testing.T) {
+	stub, h := newRelationHandler(t)
+
+	// the departments of all users are loaded by a single query, the shared keys once.
+	data, errs := execute(t, h, `{ users { name department { id } } }`)
+	assert.Empty(t, errs)
+	assert.Equal(t, []interface{}{
+		map[string]interface{}{"name": "tom", "department": map[string]interface{}{"id": float64(10)}},
+		map[string]interface{}{"name": "bob", "department": map[string]interface{}{"id": float64(10)}},
+		map[string]interface{}{"name": "amy", "department": map[string]interface{}{"id": float64(20)}},
+	}, data["users"])
+	statements := stub.Statements()
+	assert.Len(t, statements, 2)
+	assert.Equal(t, "SELECT id FROM department WHERE id IN (?,?)", statements[1])
+	assert.Equal(t, []interface{}{int64(10), int64(20)}, stub.args[1])
+}
+
+func TestRelation_HasMany(t *testing.T) {
+	stub, h := newRelationHandler(t)
+
+	// the users of all departments are loaded by a single query and grouped by department_id.
+	data, errs := execute(t, h, `{ departments { id users { name } } }`)
+	assert.Empty(t, errs)
+	assert.Equal(t, []interface{}{
+		map[string]interface{}{"id": float64(10), "users": []interface{}{
+			map[string]interface{}{"name": "tom"}, map[string]interface{}{"name": "bob"},
+		}},
+		map[string]interface{}{"id": float64(20), "users": []interface{}{
+			map[string]interface{}{"name": "amy"},
+		}},
+	}, data["departments"])
+	statements := stub.Statements()
+	assert.Len(t, statements, 2)
+	assert.Contains(t, statements[1], "FROM user WHERE")
+	assert.Contains(t, statements[1], "department_id IN (?,?)")
+	assert.Equal(t, []interface{}{int64(10), int64(20)}, stub.args[1])
+}
+
+func TestRelation_ManyToMany(t *testing.T) {
+	stub, h := newRelationHandler(t)
+
+	// the links of all users are loaded first, then the linked roles, each query once.
+	data, errs := execute(t, h, `{ users { name roles { name } } }`)
+	assert.Empty(t, errs)
+	assert.Equal(t, []interface{}{
+		map[string]interface{}{"name": "tom", "roles": []interface{}{
+			map[string]interface{}{"name": "admin"}, map[string]interface{}{"name": "dev"},
+		}},
+		map[string]interface{}{"name": "bob", "roles": []interface{}{
+			map[string]interface{}{"name": "admin"},
+		}},
+		map[string]interface{}{"name": "amy", "roles": []interface{}{}},
+	}, data["users"])
+	statements := stub.Statements()
+	assert.Len(t, statements, 3)
+	assert.Equal(t, "SELECT user_id,role_id FROM user_role WHERE user_id IN (?,?,?)", statements[1])
+	assert.Equal(t, []interface{}{int64(1), int64(2), int64(3)}, stub.args[1])
+	assert.Contains(t, statements[2], "FROM role WHERE")
+	assert.Contains(t, statements[2], "id IN (?,?)")
+	assert.Equal(t, []interface{}{int64(100), int64(101)}, stub.args[2])
+}
+
+func TestRelation_Limit(t *testing.T) {
+	stub, h := newRelationHandler(t)
+
+	// a limit applies to the rows of each parent, so the rows of each key are queried on their own.
+	_, errs := execute(t, h, `{ departments { users(limit: { count: 1 }) { name } } }`)
+	assert.Empty(t, errs)
+	statements := stub.Statements()
+	assert.Len(t, statements, 3)
+	for i, key := range []int64{10, 20} {
+		assert.Contains(t, statements[i+1], "department_id = ?")
+		assert.Contains(t, statements[i+1], "LIMIT 0,1")
+		assert.Equal(t, []interface{}{key}, stub.args[i+1])
+	}
+
+	stub.statements, stub.args = nil, nil
+	_, errs = execute(t, h, `{ users { roles(limit: { count: 1 }) { name } } }`)
+	assert.Empty(t, errs)
+	statements = stub.Statements()
+	assert.Len(t, statements, 4)
+	for _, statement := range statements[1:] {
+		assert.Contains(t, statement, "id IN (SELECT role_id FROM user_role WHERE user_id = ?)")
+	}
+}
+
+func TestRelation_Wrappers(t *testing.T) {
+	id := &Column{Type: Int, Name: "id"}
+	id.SetPrimaryKey()
+	department := newTestNode("departments", "department", "department", id)
+	department.fields = append(department.fields, department.Adapter().HasMany("users", "user", "id", "department_id").NonNull())
+	user := newTestNode("users", "user", "user", &Column{Type: Int, Name: "id"}, &Column{Type: Int, Name: "department_id"})
+	user.fields = append(user.fields, user.Adapter().BelongsTo("department", "department", "department_id", "id").NonNull())
+
+	registry := core.NewRegistry()
+	registry.Register(department)
+	registry.Register(user)
+	_ = newHandler(t, registry)
+
+	departmentObject, err := registry.GetObject("department")
+	assert.NoError(t, err)
+	assert.Equal(t, "[users]!", departmentObject.Fields()["users"].Type.String())
+	userObject, err := registry.GetObject("user")
+	assert.NoError(t, err)
+	assert.Equal(t, "departments!", userObject.Fields()["department"].Type.String())
+}
@@ -0,0 +1,279 @@
+package adapter
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Finovate/go-gql-builder/pkg/core"
+)
+
+// timeLayouts the text formats of the date and time values returned by drivers that do not parse them,
+// e.g. MySQL without parseTime=true.
+var timeLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02 15:04:05.999999999Z07:00",
+	"2006-01-02 15:04:05.999999999",
+	"2006-01-02T15:04:05.999999999",
+	"2006-01-02",
+}
+
+// scanRows reads the rows into maps keyed by the result columns. The values are converted into the Go type of
+// the declared column with the same alias, the other result columns are converted by their database type,
+// see inferColumnType. NULL is always nil.
+func (d *DefaultSqlAdapter) scanRows(rows *sql.Rows) ([]map[string]interface{}, error) {
+	columnTypes, err := rows.ColumnTypes()
+	if err != nil {
+		return nil, err
+	}
+
+	types := make([]ColumnType, len(columnTypes))
+	for i, columnType := range columnTypes {
+		if column, ok := d.columnsByAlias[columnType.Name()]; ok && column.Type != "" {
+			types[i] = column.Type
+		} else {
+			types[i] = inferColumnType(columnType.DatabaseTypeName())
+		}
+	}
+
+	cache := make([]interface{}, len(columnTypes)) // 临时存储每行数据
+	for i := range cache {                         // 为每一列初始化一个指针
+		var a interface{}
+		cache[i] = &a
+	}
+	list := make([]map[string]interface{}, 0) //返回的切片
+	for rows.Next() {
+		if err := rows.Scan(cache...); err != nil {
+			return nil, err
+		}
+
+		item := make(map[string]interface{}, len(columnTypes))
+		for i, columnType := range columnTypes {
+			val, err := types[i].convert(*(cache[i].(*interface{})))
+			if err != nil {
+				return nil, fmt.Errorf("column %s: %w", columnType.Name(), err)
+			}
+			item[columnType.Name()] = val
+		}
+		d.setTypename(item)
+		list = append(list, item)
+	}
+	return list, rows.Err()
+}
+
+// intTypes and bigIntTypes the integer type names of MySQL, PostgreSQL and SQLite,
+// the names are matched exactly, e.g. POINT and INTERVAL are not integers.
+var (
+	intTypes = map[string]bool{
+		"TINYINT": true, "SMALLINT": true, "MEDIUMINT": true, "INT": true, "INTEGER": true,
+		"INT2": true, "INT4": true, "SMALLSERIAL": true, "SERIAL": true, "YEAR": true,
+	}
+	bigIntTypes = map[string]bool{"BIGINT": true, "INT8": true, "BIGSERIAL": true}
+)
+
+// inferColumnType returns the column type of a database type name reported by the driver, e.g. BIGINT -> Int.
+// Other types are read as String, the values of drivers that report no type are kept as they are.
+func inferColumnType(databaseType string) ColumnType {
+	databaseType = strings.ToUpper(databaseType)
+	// e.g. UNSIGNED BIGINT of MySQL.
+	integerType := strings.TrimSpace(strings.TrimSuffix(strings.TrimPrefix(databaseType, "UNSIGNED "), " UNSIGNED"))
+	switch {
+	case databaseType == "BOOL" || databaseType == "BOOLEAN":
+		return Boolean
+	case bigIntTypes[integerType]:
+		return BigInt
+	case intTypes[integerType]:
+		return Int
+	case strings.Contains(databaseType, "DECIMAL") || strings.Contains(databaseType, "NUMERIC"):
+		return Decimal
+	case strings.Contains(databaseType, "FLOAT") || strings.Contains(databaseType, "DOUBLE") || databaseType == "REAL":
+		return Float
+	case strings.HasPrefix(databaseType, "DATETIME") || strings.HasPrefix(databaseType, "TIMESTAMP"):
+		return DateTime
+	case databaseType == "DATE":
+		return Date
+	case databaseType == "TIME" || databaseType == "TIMETZ":
+		return Time
+	case databaseType == "JSON" || databaseType == "JSONB":
+		return JSON
+	case databaseType == "":
+		return ""
+	default:
+		return String
+	}
+}
+
+// convert converts a value scanned by the driver into the Go type of the column:
+// Int -> int64, BigInt -> int64 or uint64, Float -> float64, Boolean -> bool, DateTime and Date -> time.Time,
+// JSON -> json.RawMessage, Time, Decimal, ID and String -> string.
+// Values of columns without a type, or of a type registered with core.NodeRegistry.RegisterScalar,
+// are kept as they are, except []byte becomes string, the Serialize of the scalar converts them.
+// Decimals are kept as their exact text, so no precision is lost.
+func (t ColumnType) convert(value interface{}) (interface{}, error) {
+	if value == nil {
+		return nil, nil
+	}
+	if bytesVal, ok := value.([]byte); ok {
+		value = string(bytesVal)
+	}
+
+	switch t {
+	case Int:
+		return toInt(value)
+	case BigInt:
+		if v, ok := value.(uint64); ok {
+			return v, nil
+		}
+		if v, ok := value.(string); ok {
+			if u, err := strconv.ParseUint(v, 10, 64); err == nil && u > math.MaxInt64 {
+				return u, nil
+			}
+		}
+		return toInt(value)
+	case Float:
+		return toFloat(value)
+	case Boolean:
+		return toBool(value)
+	case DateTime, Date:
+		return toTime(value)
+	case Time:
+		if v, ok := value.(time.Time); ok {
+			return v.Format(core.TimeLayout), nil
+		}
+		return toString(value), nil
+	case JSON:
+		return json.RawMessage(toString(value)), nil
+	case Decimal:
+		return toDecimal(value)
+	case String, ID:
+		return toString(value), nil
+	default:
+		return value, nil
+	}
+}
+
+// normalizeNumber converts the values of the integer and float kinds into int64, uint64 or float64,
+// e.g. the int8 or float32 values of some drivers, the other values are returned as they are.
+func normalizeNumber(value interface{}) interface{} {
+	v := reflect.ValueOf(value)
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return v.Int()
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return v.Uint()
+	case reflect.Float32, reflect.Float64:
+		return v.Float()
+	default:
+		return value
+	}
+}
+
+func toInt(value interface{}) (interface{}, error) {
+	switch v := normalizeNumber(value).(type) {
+	case int64:
+		return v, nil
+	case uint64:
+		if v > math.MaxInt64 {
+			return nil, fmt.Errorf("%d overflows Int", v)
+		}
+		return int64(v), nil
+	case bool:
+		if v {
+			return int64(1), nil
+		}
+		return int64(0), nil
+	case float64:
+		if v != math.Trunc(v) {
+			return nil, fmt.Errorf("%v is not an integer", v)
+		}
+		if v < math.MinInt64 || v >= math.MaxInt64 {
+			return nil, fmt.Errorf("%v overflows Int", v)
+		}
+		return int64(v), nil
+	case string:
+		return strconv.ParseInt(v, 10, 64)
+	default:
+		return nil, fmt.Errorf("cannot convert %T to Int", value)
+	}
+}
+
+func toFloat(value interface{}) (interface{}, error) {
+	switch v := normalizeNumber(value).(type) {
+	case float64:
+		return v, nil
+	case int64:
+		return float64(v), nil
+	case uint64:
+		return float64(v), nil
+	case string:
+		return strconv.ParseFloat(v, 64)
+	default:
+		return nil, fmt.Errorf("cannot convert %T to Float", value)
+	}
+}
+
+func toBool(value interface{}) (interface{}, error) {
+	switch v := normalizeNumber(value).(type) {
+	case bool:
+		return v, nil
+	case int64:
+		return v != 0, nil
+	case uint64:
+		return v != 0, nil
+	case float64:
+		if v != 0 && v != 1 {
+			return nil, fmt.Errorf("%v is not a Boolean", v)
+		}
+		return v == 1, nil
+	case string:
+		return strconv.ParseBool(v)
+	default:
+		return nil, fmt.Errorf("cannot convert %T to Boolean", value)
+	}
+}
+
+func toTime(value interface{}) (interface{}, error) {
+	switch v := value.(type) {
+	case time.Time:
+		return v, nil
+	case string:
+		for _, layout := range timeLayouts {
+			if t, err := time.Parse(layout, v); err == nil {
+				return t, nil
+			}
+		}
+		return nil, fmt.Errorf("cannot parse %q as DateTime", v)
+	default:
+		return nil, fmt.Errorf("cannot convert %T to DateTime", value)
+	}
+}
+
+func toDecimal(value interface{}) (interface{}, error) {
+	switch v := value.(type) {
+	case string:
+		return v, nil
+	case int64:
+		return strconv.FormatInt(v, 10), nil
+	case float64:
+		// the driver has already rounded it, the shortest representation keeps what is left.
+		return strconv.FormatFloat(v, 'f', -1, 64), nil
+	default:
+		return nil, fmt.Errorf("cannot convert %T to Decimal", value)
+	}
+}
+
+func toString(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case time.Time:
+		return v.Format(time.RFC3339Nano)
+	default:
+		return fmt.Sprint(v)
+	}
+}
@@ -0,0 +1,170 @@
+package adapter
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/language/ast"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Finovate/go-gql-builder/pkg/core"
+)
+
+func TestInferColumnType(t *testing.T) {
+	tests := map[string]ColumnType{
+		"BOOLEAN":         Boolean,
+		"bool":            Boolean,
+		"TINYINT":         Int,
+		"SMALLINT":        Int,
+		"MEDIUMINT":       Int,
+		"INT":             Int,
+		"integer":         Int,
+		"INT4":            Int,
+		"UNSIGNED INT":    Int,
+		"SERIAL":          Int,
+		"YEAR":            Int,
+		"BIGINT":          BigInt,
+		"UNSIGNED BIGINT": BigInt,
+		"INT8":            BigInt,
+		"BIGSERIAL":       BigInt,
+		"DECIMAL":         Decimal,
+		"NUMERIC":         Decimal,
+		"FLOAT":           Float,
+		"DOUBLE":          Float,
+		"REAL":            Float,
+		"DATETIME":        DateTime,
+		"TIMESTAMPTZ":     DateTime,
+		"DATE":            Date,
+		"TIME":            Time,
+		"JSONB":           JSON,
+		"VARCHAR":         String,
+		"POINT":           String,
+		"INTERVAL":        String,
+		"TINYTEXT":        String,
+		"":                "",
+	}
+	for databaseType, expected := range tests {
+		assert.Equalf(t, expected, inferColumnType(databaseType), "database type %q", databaseType)
+	}
+}
+
+// namedInt is an integer type of a driver.
+type namedInt int16
+
+func TestColumnType_Convert(t *testing.T) {
+	at := time.Date(2024, 1, 2, 15, 4, 5, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		t        ColumnType
+		value    interface{}
+		expected interface{}
+		err      bool
+	}{
+		{"nil", Int, nil, nil, false},
+		{"Int bytes", Int, []byte("42"), int64(42), false},
+		{"Int int32", Int, int32(7), int64(7), false},
+		{"Int bool", Int, true, int64(1), false},
+		{"Int float", Int, float64(3), int64(3), false},
+		{"Int int8", Int, int8(-8), int64(-8), false},
+		{"Int int16", Int, int16(300), int64(300), false},
+		{"Int int", Int, 9, int64(9), false},
+		{"Int uint8", Int, uint8(255), int64(255), false},
+		{"Int uint16", Int, uint16(65535), int64(65535), false},
+		{"Int uint32", Int, uint32(1 << 31), int64(1 << 31), false},
+		{"Int uint64", Int, uint64(12), int64(12), false},
+		{"Int float32", Int, float32(4), int64(4), false},
+		{"Int named", Int, namedInt(6), int64(6), false},
+		{"Int fraction", Int, 3.5, nil, true},
+		{"Int float32 fraction", Int, float32(0.5), nil, true},
+		{"Int overflow", Int, uint64(1 << 63), nil, true},
+		{"Int float overflow", Int, 1e19, nil, true},
+		{"Int invalid", Int, []int{1}, nil, true},
+		{"BigInt uint64", BigInt, uint64(1 << 63), uint64(1 << 63), false},
+		{"BigInt unsigned text", BigInt, "9223372036854775808", uint64(1 << 63), false},
+		{"BigInt text", BigInt, "-5", int64(-5), false},
+		{"Float text", Float, "1.5", 1.5, false},
+		{"Float float32", Float, float32(0.5), 0.5, false},
+		{"Float int", Float, 3, float64(3), false},
+		{"Float int32", Float, int32(-2), float64(-2), false},
+		{"Float int64", Float, int64(7), float64(7), false},
+		{"Float uint64", Float, uint64(1 << 63), float64(1 << 63), false},
+		{"Float invalid", Float, true, nil, true},
+		{"Boolean int", Boolean, int64(0), false, false},
+		{"Boolean text", Boolean, "true", true, false},
+		{"Boolean int8", Boolean, int8(1), true, false},
+		{"Boolean uint8", Boolean, uint8(0), false, false},
+		{"Boolean int", Boolean, 2, true, false},
+		{"Boolean float", Boolean, float64(1), true, false},
+		{"Boolean bytes", Boolean, []byte("1"), true, false},
+		{"Boolean invalid", Boolean, 1.5, nil, true},
+		{"Boolean invalid text", Boolean, "yes", nil, true},
+		{"DateTime time", DateTime, at, at, false},
+		{"DateTime text", DateTime, "2024-01-02 15:04:05", at, false},
+		{"DateTime invalid", DateTime, "yesterday", nil, true},
+		{"Date text", Date, "2024-01-02", time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC), false},
+		{"Time time", Time, at, "15:04:05", false},
+		{"Time text", Time, []byte("08:00:00"), "08:00:00", false},
+		{"JSON", JSON, []byte(`{"a":1}`), json.RawMessage(`{"a":1}`), false},
+		{"Decimal text", Decimal, []byte("12.30"), "12.30", false},
+		{"Decimal int", Decimal, int64(3), "3", false},
+		{"Decimal float", Decimal, 0.1, "0.1", false},
+		{"Decimal invalid", Decimal, true, nil, true},
+		{"String int", String, int64(3), "3", false},
+		{"String time", String, at, "2024-01-02T15:04:05Z", false},
+		{"ID", ID, int64(3), "3", false},
+		{"untyped bytes", "", []byte("x"), "x", false},
+		{"untyped value", "", int64(3), int64(3), false},
+		// the Serialize of a registered scalar converts the raw value.
+		{"registered scalar", "Money", int64(1250), int64(1250), false},
+		{"registered scalar bytes", "Money", []byte("12.50"), "12.50", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual, err := tt.t.convert(tt.value)
+			if tt.err {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, actual)
+		})
+	}
+}
+
+func TestRegisteredScalar_Serialize(t *testing.T) {
+	_, db := newStubDB(t, func(string, []interface{}) ([]string, [][]driver.Value) {
+		return []string{"id", "price"}, [][]driver.Value{{int64(1), int64(1250)}}
+	})
+	var serialized []interface{}
+	money := graphql.NewScalar(graphql.ScalarConfig{
+		Name: "Money",
+		Serialize: func(value interface{}) interface{} {
+			serialized = append(serialized, value)
+			cents, ok := value.(int64)
+			if !ok {
+				return nil
+			}
+			return fmt.Sprintf("%d.%02d", cents/100, cents%100)
+		},
+		ParseValue:   func(value interface{}) interface{} { return value },
+		ParseLiteral: func(ast.Value) interface{} { return nil },
+	})
+
+	id := &Column{Type: Int, Name: "id"}
+	id.SetPrimaryKey()
+	registry := core.NewRegistry()
+	registry.RegisterScalar("Money", money)
+	registry.Register(newTestNode("products", "product", "product", id, &Column{Type: "Money", Name: "price"}))
+	registry.SetDB(db)
+	h := newHandler(t, registry)
+
+	data, errs := execute(t, h, `{ products { price } }`)
+	assert.Empty(t, errs)
+	assert.Equal(t, []interface{}{map[string]interface{}{"price": "12.50"}}, data["products"])
+	assert.Equal(t, []interface{}{int64(1250)}, serialized)
+}
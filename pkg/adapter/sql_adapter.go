@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/graphql-go/graphql"
 	"github.com/graphql-go/graphql/language/ast"
@@ -11,12 +12,28 @@ import (
 	sqlArgument "github.com/Finovate/go-gql-builder/pkg/adapter/internal/argument"
 	"github.com/Finovate/go-gql-builder/pkg/core"
 	coreArgument "github.com/Finovate/go-gql-builder/pkg/core/argument"
+	"github.com/Finovate/go-gql-builder/pkg/dialect"
 )
 
 // SqlAdapter is a part of Node interface, which is
 // designed to bridge business objects with SQL queries.
 type SqlAdapter interface {
 	Resolve() graphql.FieldResolveFn
+	// BuildArgumentType implements core.ArgumentTypeBuilder,
+	// the input types of the arguments are derived from the table columns.
+	BuildArgumentType(arg coreArgument.Argument) graphql.Input
+	// BuildMutations implements core.MutationBuilder.
+	BuildMutations() (graphql.Fields, error)
+	// BuildQueries implements core.QueryBuilder.
+	BuildQueries() (graphql.Fields, error)
+	// DataSourceName implements core.DataSourceBinder.
+	DataSourceName() string
+	// OutputType implements core.PolymorphicNode.
+	OutputType() core.FieldType
+	// NodeFetcher refetches the rows by their primary keys, see core.NodeRegistry.EnableNodeQuery.
+	core.NodeFetcher
+	// Adapter returns the underlying DefaultSqlAdapter.
+	Adapter() *DefaultSqlAdapter
 }
 
 // DefaultSqlAdapter is a default implementation of SqlAdapter.
@@ -29,6 +46,23 @@ type DefaultSqlAdapter struct {
 	columnsByAlias map[string]*Column
 	columnsByName  map[string]*Column
 	primaryKeys    []*Column
+	// uniqueKeys 唯一索引的列名, 每个唯一索引生成一个查询单行的 field, 见 AddUniqueKey.
+	uniqueKeys [][]string
+
+	relations map[string]*Relation
+	// connection 不为 nil 时生成 Relay connection field, 见 EnableConnection.
+	connection *connection
+	// aggregateDisabled 为 true 时不生成 xAggregate field, 见 DisableAggregate.
+	aggregateDisabled bool
+
+	// dataSource 表所在的数据源名称, 为空时使用 core.DefaultDataSource.
+	dataSource string
+	// dialect 为 nil 时使用数据源或者 registry 的 dialect.
+	dialect dialect.Dialect
+	// queryTimeout 为 0 时使用 registry 的超时时间.
+	queryTimeout time.Duration
+	// discriminator 不为 nil 时表中的行属于不同的 Node 类型, 见 SetDiscriminator.
+	discriminator *discriminator
 }
 
 func NewDefaultSqlAdapter(tableName string, columns []*Column, node core.Node) *DefaultSqlAdapter {
@@ -39,9 +73,13 @@ func NewDefaultSqlAdapter(tableName string, columns []*Column, node core.Node) *
 		columnsByAlias: make(map[string]*Column),
 		columnsByName:  make(map[string]*Column),
 		primaryKeys:    make([]*Column, 0),
+		relations:      make(map[string]*Relation),
 	}
 
 	for _, column := range columns {
+		if column.Alias == "" {
+			column.Alias = column.Name
+		}
 		d.tableColumns = append(d.tableColumns, column)
 		d.columnsByAlias[column.Alias] = column
 		d.columnsByName[column.Name] = column
@@ -53,95 +91,292 @@ func NewDefaultSqlAdapter(tableName string, columns []*Column, node core.Node) *
 	return d
 }
 
+// ColumnName translates a GraphQL field name into the column name of the table,
+// only the declared columns are accepted, so client input can not reference arbitrary columns.
+func (d *DefaultSqlAdapter) ColumnName(alias string) (string, bool) {
+	column, ok := d.columnsByAlias[alias]
+	if !ok {
+		return "", false
+	}
+	return column.Name, true
+}
+
+// SetDataSource binds the table to a data source registered with core.NodeRegistry.RegisterDataSource,
+// the registry checks that it exists when the schema is built.
+func (d *DefaultSqlAdapter) SetDataSource(name string) {
+	d.dataSource = name
+}
+
+func (d *DefaultSqlAdapter) DataSourceName() string {
+	return d.dataSource
+}
+
+// SetDialect selects the SQL dialect of the table, it overrides the dialect of the data source and the registry.
+func (d *DefaultSqlAdapter) SetDialect(sqlDialect dialect.Dialect) {
+	d.dialect = sqlDialect
+}
+
+// sqlDialect returns the dialect of the adapter, or the dialect of its data source when the adapter has none.
+func (d *DefaultSqlAdapter) sqlDialect() dialect.Dialect {
+	if d.dialect != nil {
+		return d.dialect
+	}
+	return d.node.GetRegistry().DataSourceDialect(d.dataSource)
+}
+
+// SetQueryTimeout limits the time of every statement on the table, it overrides the timeout of the registry,
+// see core.NodeRegistry.SetQueryTimeout.
+func (d *DefaultSqlAdapter) SetQueryTimeout(timeout time.Duration) {
+	d.queryTimeout = timeout
+}
+
+// withTimeout derives the context of a statement from the request context, limited by the query timeout.
+// The querier must be obtained from the request context, a transaction begun with the derived context
+// would be rolled back once the statement finishes.
+func (d *DefaultSqlAdapter) withTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	timeout := d.queryTimeout
+	if timeout == 0 {
+		timeout = d.node.GetRegistry().QueryTimeout()
+	}
+	if timeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, timeout)
+}
+
+// quote quotes a table or column name with the dialect of the adapter.
+func (d *DefaultSqlAdapter) quote(name string) string {
+	return d.sqlDialect().QuoteIdentifier(name)
+}
+
+func (d *DefaultSqlAdapter) BuildArgumentType(arg coreArgument.Argument) graphql.Input {
+	typedArg, ok := arg.(sqlArgument.TypedArgument)
+	if !ok {
+		return nil
+	}
+
+	columns := make([]*sqlArgument.ArgumentColumn, 0, len(d.tableColumns))
+	for _, column := range d.tableColumns {
+		columns = append(columns, &sqlArgument.ArgumentColumn{
+			Name: column.Alias,
+			Type: d.inputType(column.Type),
+		})
+	}
+	return typedArg.BuildArgumentType(typeName(d.node.Type()), columns)
+}
+
 func (d *DefaultSqlAdapter) Resolve() graphql.FieldResolveFn {
 	return func(p graphql.ResolveParams) (interface{}, error) {
-		customFields := make([]*ast.Field, 0)
-		for _, field := range p.Info.FieldASTs {
-			if field.Name.Value == d.node.Name() {
-				selections := field.SelectionSet.Selections
-				for _, selection := range selections {
-					customFields = append(customFields, selection.(*ast.Field))
-				}
-				break
-			}
+		qc, err := d.buildQuery(p)
+		if err != nil {
+			return nil, err
 		}
+		return d.query(p.Context, qc)
+	}
+}
 
-		var customCollect []string
-		for _, field := range customFields {
-			if _, ok := d.columnsByName[field.Name.Value]; ok {
-				customCollect = append(customCollect, field.Name.Value)
-			}
+// Adapter returns the adapter itself, it allows the framework to reach the DefaultSqlAdapter
+// of a Node that embeds the SqlAdapter interface, e.g. to resolve the relations targeting the Node.
+func (d *DefaultSqlAdapter) Adapter() *DefaultSqlAdapter {
+	return d
+}
+
+// buildQuery builds the select statement of the field being resolved,
+// the selected columns come from the selection set and the conditions from the field arguments,
+// required columns are selected whether they are part of the selection set or not.
+func (d *DefaultSqlAdapter) buildQuery(p graphql.ResolveParams, required ...*Column) (*sqlArgument.QueryClauses, error) {
+	qc := d.selectQuery(p, required...)
+	args := make(map[string]interface{}, len(p.Args))
+	for name, value := range p.Args {
+		// the arguments declared by core.Field.AddArgument are handled by the resolver of the field.
+		if !d.node.GetRegistry().IsFieldArgument(p.Info, name) {
+			args[name] = value
 		}
+	}
+	if err := d.applyArguments(qc, args); err != nil {
+		return nil, err
+	}
+	return qc, nil
+}
 
-		if len(customCollect) <= 0 {
-			for _, pk := range d.primaryKeys {
-				customCollect = append(customCollect, pk.Name)
-			}
-			if len(customCollect) == 0 {
-				customCollect = []string{"*"}
-			}
+// selectQuery builds the select statement of the field being resolved without any condition.
+func (d *DefaultSqlAdapter) selectQuery(p graphql.ResolveParams, required ...*Column) *sqlArgument.QueryClauses {
+	return sqlArgument.NewQueryClauses(strings.Join(d.selectColumns(p, required...), ","), d.quote(d.tableName))
+}
+
+// applyArguments validates the arguments supplied by the client and combines them into the statement.
+func (d *DefaultSqlAdapter) applyArguments(qc *sqlArgument.QueryClauses, args map[string]interface{}) error {
+	for name, value := range args {
+		arg, err := d.argument(name, value)
+		if err != nil {
+			return err
+		}
+		if sqlArg, ok := arg.(sqlArgument.SqlArgument); ok {
+			sqlArg.CombineSql(qc)
 		}
+	}
 
-		qc := sqlArgument.NewQueryClauses(strings.Join(customCollect, ","), d.tableName)
+	return nil
+}
+
+// argument creates the argument of the Node by name and validates the value supplied by the client.
+func (d *DefaultSqlAdapter) argument(name string, value interface{}) (coreArgument.Argument, error) {
+	arg := d.node.GetRegistry().Argument(d.node.Type(), name)
+	if arg == nil {
+		return nil, fmt.Errorf("argument typename is not exist")
+	}
 
-		for name, value := range p.Args {
-			arg := coreArgument.Factory(name)
-			if arg == nil {
-				return nil, fmt.Errorf("argument typename is not exist")
+	if sqlArg, ok := arg.(sqlArgument.SqlArgument); ok {
+		sqlArg.SetColumnMapper(d)
+		sqlArg.SetDialect(d.sqlDialect())
+	}
+	if err := arg.Validate(value); err != nil {
+		return nil, err
+	}
+	return arg, nil
+}
+
+// selectColumns returns the columns required by the selection set, including the columns
+// referenced by the selected relations, the primary keys are selected when nothing else is.
+func (d *DefaultSqlAdapter) selectColumns(p graphql.ResolveParams, required ...*Column) []string {
+	return d.fieldColumns(collectFields(p), required...)
+}
+
+// fieldColumns returns the columns required by the fields, see selectColumns.
+func (d *DefaultSqlAdapter) fieldColumns(fields []*ast.Field, required ...*Column) []string {
+	selected := make(map[string]struct{})
+	var customCollect []string
+	collect := func(column *Column) {
+		if _, ok := selected[column.Name]; ok {
+			return
+		}
+		selected[column.Name] = struct{}{}
+		customCollect = append(customCollect, column.selectExpr(d.sqlDialect()))
+	}
+
+	for _, column := range required {
+		collect(column)
+	}
+	// the type of every row is picked by the discriminator, whatever the selection.
+	if column, _ := d.discriminatorColumn(); column != nil {
+		collect(column)
+	}
+	for _, field := range fields {
+		if column, ok := d.columnsByAlias[field.Name.Value]; ok {
+			collect(column)
+		}
+		// the global ID is made of the primary keys, see core.NodeRegistry.EnableNodeQuery.
+		if field.Name.Value == core.GlobalIDField && d.node.GetRegistry().NodeQueryEnabled() {
+			for _, pk := range d.primaryKeys {
+				collect(pk)
 			}
-			err := arg.Validate(value)
-			if err != nil {
-				return nil, err
+		}
+		// rawId is the id column replaced by the global ID.
+		if field.Name.Value == core.RawIDField && d.node.GetRegistry().NodeQueryEnabled() {
+			if column, ok := d.columnsByAlias[core.GlobalIDField]; ok {
+				collect(column)
 			}
-
-			sqlArg, ok := arg.(sqlArgument.SqlArgument)
-			if ok {
-				sqlArg.CombineSql(qc)
-				//switch x := sqlArg.(type) {
-				//case *argument.FilterArgument:
-				//	qc.SetWhere(x.ParseSqlValue())
-				//}
+		}
+		if relation, ok := d.relations[field.Name.Value]; ok {
+			if column, ok := d.columnsByName[relation.localColumn]; ok {
+				collect(column)
 			}
 		}
+	}
 
-		sql, err := qc.ToSql()
-		if err != nil {
-			return nil, err
+	if len(customCollect) <= 0 {
+		for _, pk := range d.primaryKeys {
+			collect(pk)
 		}
-
-		rows, err := d.node.GetRegistry().GetDB().QueryContext(context.Background(), sql)
-		if err != nil {
-			return nil, err
+		if len(customCollect) == 0 {
+			customCollect = []string{"*"}
 		}
+	}
+	return customCollect
+}
 
-		defer rows.Close()
+// query runs the statement on the data source of the table with the ReadQuerier of the context,
+// so it is sent to a replica, or takes part in the transaction of a mutation.
+func (d *DefaultSqlAdapter) query(ctx context.Context, qc *sqlArgument.QueryClauses) ([]map[string]interface{}, error) {
+	sql, args, err := qc.ToSql()
+	if err != nil {
+		return nil, err
+	}
 
-		columns, _ := rows.Columns()
+	querier, err := d.node.GetRegistry().ReadQuerier(ctx, d.dataSource)
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := d.withTimeout(ctx)
+	defer cancel()
+	rows, err := querier.QueryContext(ctx, dialect.Rebind(d.sqlDialect(), sql), args...)
+	if err != nil {
+		return nil, core.ContextError(ctx, err)
+	}
+
+	defer rows.Close()
+
+	list, err := d.scanRows(rows)
+	return list, core.ContextError(ctx, err)
+}
+
+// collectFields returns the fields selected on the field being resolved, including the fields of fragments.
+func collectFields(p graphql.ResolveParams) []*ast.Field {
+	selectionSets := make([]*ast.SelectionSet, 0, len(p.Info.FieldASTs))
+	for _, field := range p.Info.FieldASTs {
+		selectionSets = append(selectionSets, field.SelectionSet)
+	}
+	return collectSelections(p, selectionSets)
+}
 
-		cache := make([]interface{}, len(columns)) // 临时存储每行数据
-		for i := range cache {                     // 为每一列初始化一个指针
-			var a interface{}
-			cache[i] = &a
+// nestedFields returns the fields selected under the path of the field being resolved, e.g. edges.node.
+func nestedFields(p graphql.ResolveParams, path ...string) []*ast.Field {
+	fields := collectFields(p)
+	for _, name := range path {
+		selectionSets := make([]*ast.SelectionSet, 0)
+		for _, field := range fields {
+			if field.Name.Value == name {
+				selectionSets = append(selectionSets, field.SelectionSet)
+			}
 		}
-		var list []map[string]interface{} //返回的切片
-		for rows.Next() {
-			_ = rows.Scan(cache...)
+		fields = collectSelections(p, selectionSets)
+	}
+	return fields
+}
 
-			item := make(map[string]interface{})
-			for i, col := range columns {
-				val := *(cache[i].(*interface{})) // 获取实际类型的值
+// collectSelections returns the fields of the selection sets, including the fields of fragments.
+func collectSelections(p graphql.ResolveParams, selectionSets []*ast.SelectionSet) []*ast.Field {
+	fields := make([]*ast.Field, 0)
+	visited := make(map[string]struct{})
 
-				if bytesVal, ok := val.([]byte); ok {
-					val = string(bytesVal)
+	var collect func(selectionSet *ast.SelectionSet)
+	collect = func(selectionSet *ast.SelectionSet) {
+		if selectionSet == nil {
+			return
+		}
+		for _, selection := range selectionSet.Selections {
+			switch selection := selection.(type) {
+			case *ast.Field:
+				fields = append(fields, selection)
+			case *ast.InlineFragment:
+				collect(selection.SelectionSet)
+			case *ast.FragmentSpread:
+				name := selection.Name.Value
+				if _, ok := visited[name]; ok {
+					continue
+				}
+				visited[name] = struct{}{}
+				if fragment, ok := p.Info.Fragments[name].(*ast.FragmentDefinition); ok {
+					collect(fragment.SelectionSet)
 				}
-				item[col] = val
 			}
-			list = append(list, item)
 		}
+	}
 
-		return list, nil
-		//return []map[string]interface{}{{"id": "1", "name": "Example Product", "price": 99.99}}, nil
+	for _, selectionSet := range selectionSets {
+		collect(selectionSet)
 	}
+	return fields
 }
 
 // DTO to GraphQL Object
@@ -161,10 +396,55 @@ func (c *Column) IsPrimaryKey() bool {
 	return c.isPrimaryKey
 }
 
+// selectExpr selects the column under its alias, so the rows are keyed by the GraphQL field name.
+func (c *Column) selectExpr(d dialect.Dialect) string {
+	if c.Alias == c.Name {
+		return d.QuoteIdentifier(c.Name)
+	}
+	return fmt.Sprintf("%s AS %s", d.QuoteIdentifier(c.Name), d.QuoteIdentifier(c.Alias))
+}
+
+// ColumnType decides the Go type the values of the column are scanned into, see ColumnType.convert.
+// Columns without a type are converted by the type the database reports.
 type ColumnType string
 
 const (
-	Int    ColumnType = "Int"
-	Float  ColumnType = "Float"
-	String ColumnType = "String"
+	Int      ColumnType = "Int"
+	Float    ColumnType = "Float"
+	String   ColumnType = "String"
+	Boolean  ColumnType = "Boolean"
+	ID       ColumnType = "ID"
+	DateTime ColumnType = "DateTime"
+	Date     ColumnType = "Date"
+	Time     ColumnType = "Time"
+	JSON     ColumnType = "JSON"
+	BigInt   ColumnType = "BigInt"
+	// Decimal values are read as their exact text, e.g. "12.30".
+	Decimal ColumnType = "Decimal"
 )
+
+// inputType returns the GraphQL type of the column values, a ColumnType is resolved as the FieldType of
+// the same name, so the types registered with core.NodeRegistry.RegisterScalar can be used as well.
+// Columns without a type, or whose type is unknown or not an input type, are treated as String.
+func (d *DefaultSqlAdapter) inputType(t ColumnType) graphql.Input {
+	if t == "" {
+		return graphql.String
+	}
+	if scalar, ok := d.node.GetRegistry().ScalarType(core.FieldType(t)); ok {
+		if input, ok := scalar.(graphql.Input); ok {
+			return input
+		}
+	}
+	return graphql.String
+}
+
+// typeName converts a Node type into a GraphQL type name prefix, e.g. user_group -> UserGroup.
+func typeName(t core.FieldType) string {
+	words := strings.Split(string(t), "_")
+	for i, word := range words {
+		if word != "" {
+			words[i] = strings.ToUpper(word[:1]) + word[1:]
+		}
+	}
+	return strings.Join(words, "")
+}
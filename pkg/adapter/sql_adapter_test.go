@@ -0,0 +1,169 @@
+package adapter
+
+import (
+	"context"
+	"database/sql/driver"
+	"encoding/json"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/graphql-go/graphql"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Finovate/go-gql-builder/pkg/core"
+)
+
+func TestBuildQuery_FieldArguments(t *testing.T) {
+	stub, db := newStubDB(t, func(query string, _ []interface{}) ([]string, [][]driver.Value) {
+		if query == "SELECT id FROM department" {
+			return []string{"id"}, [][]driver.Value{{int64(1)}}
+		}
+		return []string{"name", "department_id"}, [][]driver.Value{{"tom", int64(1)}}
+	})
+	id := &Column{Type: Int, Name: "id"}
+	id.SetPrimaryKey()
+	department := newTestNode("departments", "department", "department", id)
+	users := department.Adapter().HasMany("users", "user", "id", "department_id")
+	users.AddArgument("active", core.FieldTypeBoolean).SetDefault(true)
+	department.fields = append(department.fields, users)
+
+	registry := core.NewRegistry()
+	registry.Register(department)
+	userID := &Column{Type: Int, Name: "id"}
+	userID.SetPrimaryKey()
+	registry.Register(newTestNode("users", "user", "user",
+		userID, &Column{Name: "name"}, &Column{Type: Int, Name: "department_id"}))
+	registry.SetDB(db)
+	h := newHandler(t, registry)
+
+	// the declared argument is left to the resolver, the arguments of the Node are applied.
+	data, errs := execute(t, h, `{ departments { users(active: false, filter: { name: { equal: "tom" } }) { name } } }`)
+	assert.Empty(t, errs)
+	assert.Equal(t, []interface{}{
+		map[string]interface{}{"users": []interface{}{map[string]interface{}{"name": "tom"}}},
+	}, data["departments"])
+	assert.Len(t, stub.Statements(), 2)
+	assert.Contains(t, stub.Statements()[1], "name = ?")
+
+	output, err := registry.GetOutput("department")
+	assert.NoError(t, err)
+	info := graphql.ResolveInfo{FieldName: "users", ParentType: output.(*graphql.Object)}
+	assert.True(t, registry.IsFieldArgument(info, "active"))
+	assert.False(t, registry.IsFieldArgument(info, "filter"))
+}
+
+func TestArgument_Unknown(t *testing.T) {
+	n := newUserNode()
+	registry := core.NewRegistry()
+	registry.Register(n)
+	_ = newHandler(t, registry)
+
+	_, err := n.Adapter().argument("unknown", 1)
+	assert.EqualError(t, err, "argument typename is not exist")
+}
+
+// executeCodes runs the query on a database blocking every statement until its context is done,
+// the statements time out after timeout, the request is canceled once the first statement runs when cancel is set.
+// It returns the codes in the extensions of the errors.
+func executeCodes(t *testing.T, query string, timeout time.Duration, cancel bool) []interface{} {
+	t.Helper()
+	stub, db := newStubDB(t, nil)
+	stub.block = true
+	n := newUserNode()
+	n.Adapter().SetQueryTimeout(timeout)
+	registry := core.NewRegistry()
+	registry.Register(n)
+	registry.SetDB(db)
+	h := newHandler(t, registry)
+
+	ctx, stop := context.WithCancel(context.Background())
+	defer stop()
+	if cancel {
+		go func() {
+			for len(stub.Statements()) == 0 {
+				time.Sleep(time.Millisecond)
+			}
+			stop()
+		}()
+	}
+
+	body, _ := json.Marshal(map[string]interface{}{"query": query})
+	req := httptest.NewRequest("POST", "/graphql", strings.NewReader(string(body))).WithContext(ctx)
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+
+	var result struct {
+		Errors []struct {
+			Extensions map[string]interface{} `json:"extensions"`
+		} `json:"errors"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &result); err != nil {
+		t.Fatalf("invalid response %q: %v", w.Body.String(), err)
+	}
+	codes := make([]interface{}, len(result.Errors))
+	for i, e := range result.Errors {
+		codes[i] = e.Extensions["code"]
+	}
+	return codes
+}
+
+func TestQuery_Timeout(t *testing.T) {
+	codes := executeCodes(t, `{ users { id } }`, 10*time.Millisecond, false)
+	assert.Equal(t, []interface{}{core.ErrorCodeTimeout}, codes)
+
+	// a request canceled by the client did not exceed the timeout, graphql-go reports the cancellation
+	// of the request itself, see TestContextError for the error of the statement.
+	codes = executeCodes(t, `{ users { id } }`, time.Minute, true)
+	assert.Len(t, codes, 1)
+	assert.NotContains(t, codes, core.ErrorCodeTimeout)
+}
+
+func TestEnumColumn(t *testing.T) {
+	stub, db := newStubDB(t, func(query string, _ []interface{}) ([]string, [][]driver.Value) {
+		return []string{"id", "status"}, [][]driver.Value{{int64(1), []byte("active")}, {int64(2), "disabled"}}
+	})
+	id := &Column{Type: Int, Name: "id"}
+	id.SetPrimaryKey()
+	registry := core.NewRegistry()
+	registry.RegisterEnum(&core.Enum{
+		Type: "UserStatus",
+		Values: []*core.EnumValue{
+			{Name: "ACTIVE", Value: "active"},
+			{Name: "DISABLED", Value: "disabled"},
+		},
+	})
+	registry.Register(newTestNode("users", "user", "user", id, &Column{Type: "UserStatus", Name: "status"}))
+	registry.SetDB(db)
+	registry.EnableMutation()
+	h := newHandler(t, registry)
+
+	// the database values are returned as the names of the enum, the names are written as the values.
+	data, errs := execute(t, h, `{ users(filter: { status: { equal: ACTIVE } }) { id status } }`)
+	assert.Empty(t, errs)
+	assert.Equal(t, []interface{}{
+		map[string]interface{}{"id": float64(1), "status": "ACTIVE"},
+		map[string]interface{}{"id": float64(2), "status": "DISABLED"},
+	}, data["users"])
+	assert.Equal(t, []interface{}{"active"}, stub.args[0])
+
+	stub.statements, stub.args = nil, nil
+	_, errs = execute(t, h, `mutation { createUser(input: { status: DISABLED }) { id } }`)
+	assert.Empty(t, errs)
+	assert.Equal(t, "INSERT INTO user (status) VALUES (?)", stub.Statements()[0])
+	assert.Equal(t, []interface{}{"disabled"}, stub.args[0])
+
+	// the values outside the enum are rejected before any statement.
+	stub.statements, stub.args = nil, nil
+	_, errs = execute(t, h, `{ users(filter: { status: { equal: BANNED } }) { id } }`)
+	assert.Len(t, errs, 1)
+	assert.Contains(t, errs[0], `Expected type "UserStatus", found BANNED.`)
+	_, errs = execute(t, h, `{ users(filter: { status: { equal: "active" } }) { id } }`)
+	assert.Len(t, errs, 1)
+	_, errs = execute(t, h, `mutation { createUser(input: { status: BANNED }) { id } }`)
+	assert.Len(t, errs, 1)
+	assert.Contains(t, errs[0], `Expected type "UserStatus", found BANNED.`)
+	assert.Empty(t, stub.Statements())
+}
@@ -0,0 +1,199 @@
+package adapter
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/Finovate/go-gql-builder/pkg/core"
+	"github.com/Finovate/go-gql-builder/pkg/core/argument"
+)
+
+// stubDB is a database answering the queries with the rows of a handler, it records every statement.
+type stubDB struct {
+	mu         sync.Mutex
+	statements []string
+	args       [][]interface{}
+	// rows returns the result of a query, no row by default.
+	rows func(query string, args []interface{}) ([]string, [][]driver.Value)
+	// lastInsertId is the id of every INSERT.
+	lastInsertId int64
+	// block makes the queries wait until their context is done.
+	block bool
+}
+
+func newStubDB(t *testing.T, rows func(query string, args []interface{}) ([]string, [][]driver.Value)) (*stubDB, *sql.DB) {
+	stub := &stubDB{rows: rows}
+	db := sql.OpenDB(stub)
+	t.Cleanup(func() { _ = db.Close() })
+	return stub, db
+}
+
+func (s *stubDB) Connect(context.Context) (driver.Conn, error) { return &stubConn{db: s}, nil }
+func (s *stubDB) Driver() driver.Driver                        { return nil }
+
+func (s *stubDB) record(query string, args []driver.NamedValue) []interface{} {
+	values := make([]interface{}, len(args))
+	for i, arg := range args {
+		values[i] = arg.Value
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.statements = append(s.statements, query)
+	s.args = append(s.args, values)
+	return values
+}
+
+// Statements returns the recorded statements without the quotes of the identifiers.
+func (s *stubDB) Statements() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	statements := make([]string, len(s.statements))
+	for i, statement := range s.statements {
+		statements[i] = strings.ReplaceAll(statement, "`", "")
+	}
+	return statements
+}
+
+type stubConn struct {
+	db *stubDB
+}
+
+func (c *stubConn) Prepare(string) (driver.Stmt, error) {
+	return nil, fmt.Errorf("prepare is not supported")
+}
+func (c *stubConn) Close() error { return nil }
+func (c *stubConn) Begin() (driver.Tx, error) {
+	c.db.record("BEGIN", nil)
+	return stubTx{db: c.db}, nil
+}
+
+func (c *stubConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	values := c.db.record(query, args)
+	if c.db.block {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}
+	rows := &stubRows{}
+	if c.db.rows != nil {
+		rows.columns, rows.values = c.db.rows(strings.ReplaceAll(query, "`", ""), values)
+	}
+	return rows, nil
+}
+
+func (c *stubConn) ExecContext(_ context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	c.db.record(query, args)
+	return stubResult{id: c.db.lastInsertId}, nil
+}
+
+type stubTx struct {
+	db *stubDB
+}
+
+func (t stubTx) Commit() error   { t.db.record("COMMIT", nil); return nil }
+func (t stubTx) Rollback() error { t.db.record("ROLLBACK", nil); return nil }
+
+type stubResult struct {
+	id int64
+}
+
+func (r stubResult) LastInsertId() (int64, error) { return r.id, nil }
+func (r stubResult) RowsAffected() (int64, error) { return 1, nil }
+
+type stubRows struct {
+	columns []string
+	values  [][]driver.Value
+	i       int
+}
+
+func (r *stubRows) Columns() []string { return r.columns }
+func (r *stubRows) Close() error      { return nil }
+func (r *stubRows) Next(dest []driver.Value) error {
+	if r.i >= len(r.values) {
+		return io.EOF
+	}
+	copy(dest, r.values[r.i])
+	r.i++
+	return nil
+}
+
+// testNode is a Node of a table whose fields are its columns.
+type testNode struct {
+	SqlAdapter
+	core.BaseNode
+	argument.DefaultArgumentBuilder
+	name      string
+	fieldType core.FieldType
+	// fields are added to the fields of the columns, e.g. the relations.
+	fields []*core.Field
+}
+
+func newTestNode(name string, fieldType core.FieldType, table string, columns ...*Column) *testNode {
+	n := &testNode{name: name, fieldType: fieldType}
+	n.SqlAdapter = NewDefaultSqlAdapter(table, columns, n)
+	return n
+}
+
+func (n *testNode) Name() string         { return n.name }
+func (n *testNode) Type() core.FieldType { return n.fieldType }
+func (n *testNode) IsList() bool         { return true }
+func (n *testNode) BuildFields() []*core.Field {
+	fields := make([]*core.Field, 0)
+	for _, column := range n.Adapter().tableColumns {
+		fieldType := core.FieldType(column.Type)
+		if fieldType == "" {
+			fieldType = core.FieldTypeString
+		}
+		fields = append(fields, core.NewNodeField(column.Alias, fieldType))
+	}
+	return append(fields, n.fields...)
+}
+
+// newUserNode returns the Node of the table user (id Int primary key, name, email).
+func newUserNode() *testNode {
+	id := &Column{Type: Int, Name: "id"}
+	id.SetPrimaryKey()
+	return newTestNode("users", "user", "user", id, &Column{Name: "name"}, &Column{Name: "email"})
+}
+
+func newHandler(t *testing.T, registry *core.NodeRegistry) http.Handler {
+	t.Helper()
+	h, err := registry.BuildHandler()
+	if err != nil {
+		t.Fatalf("BuildHandler failed: %v", err)
+	}
+	return h
+}
+
+// execute runs the query with the handler, it returns the data and the error messages.
+func execute(t *testing.T, h http.Handler, query string) (map[string]interface{}, []string) {
+	t.Helper()
+	body, _ := json.Marshal(map[string]interface{}{"query": query})
+	req := httptest.NewRequest("POST", "/graphql", strings.NewReader(string(body)))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+
+	var result struct {
+		Data   map[string]interface{} `json:"data"`
+		Errors []struct {
+			Message string `json:"message"`
+		} `json:"errors"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &result); err != nil {
+		t.Fatalf("invalid response %q: %v", w.Body.String(), err)
+	}
+	messages := make([]string, len(result.Errors))
+	for i, e := range result.Errors {
+		messages[i] = e.Message
+	}
+	return result.Data, messages
+}
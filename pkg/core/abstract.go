@@ -0,0 +1,176 @@
+package core
+
+import (
+	"fmt"
+
+	"github.com/graphql-go/graphql"
+)
+
+// TypenameKey is the key of the Node type in a result map, it tells the concrete type of a value returned by a field
+// whose type is an interface or a union, e.g. map[string]interface{}{TypenameKey: FieldTypePerson, "name": "tom"}.
+const TypenameKey = "__typename"
+
+// TypeResolver returns the Node type of a value returned by a field whose type is an interface or a union,
+// false means the type is unknown, then the TypenameKey of the value is used.
+type TypeResolver func(value interface{}) (FieldType, bool)
+
+// Interface declares a GraphQL interface implemented by Nodes, e.g. Auditable { createdAt updatedAt },
+// every implementing Node must declare the fields of the interface with the same types.
+type Interface struct {
+	Type        FieldType
+	Description string
+	Fields      []*Field
+	// Types are the Node types implementing the interface.
+	Types       []FieldType
+	ResolveType TypeResolver
+}
+
+// Union declares a GraphQL union of Nodes, e.g. Party = Person | Company.
+type Union struct {
+	Type        FieldType
+	Description string
+	// Types are the Node types of the union members.
+	Types       []FieldType
+	ResolveType TypeResolver
+}
+
+// PolymorphicNode is optionally implemented by a Node whose rows are of the types of an interface or a union,
+// e.g. a table of parties holding persons and companies. The fields of the Node then return OutputType()
+// instead of the object of the Node, an empty OutputType keeps the object.
+type PolymorphicNode interface {
+	OutputType() FieldType
+}
+
+// RegisterInterface declares an interface, its type can be used by fields like the type of a Node.
+// It should be called before the schema is built.
+func (h *NodeRegistry) RegisterInterface(iface *Interface) {
+	h.interfaces = append(h.interfaces, iface)
+}
+
+// RegisterUnion declares a union, its type can be used by fields like the type of a Node.
+// It should be called before the schema is built.
+func (h *NodeRegistry) RegisterUnion(union *Union) {
+	h.unions = append(h.unions, union)
+}
+
+// isAbstract reports whether the type is a registered interface or union.
+func (h *NodeRegistry) isAbstract(flag FieldType) bool {
+	for _, iface := range h.interfaces {
+		if iface.Type == flag {
+			return true
+		}
+	}
+	for _, union := range h.unions {
+		if union.Type == flag {
+			return true
+		}
+	}
+	return false
+}
+
+// preLoadAbstracts 创建 interface 和 union 的类型, 需要在 Node 的 object 创建之后调用,
+// interface 的 fields 在 buildAbstracts 中补充.
+func (h *NodeRegistry) preLoadAbstracts() error {
+	for _, iface := range h.interfaces {
+		h.preCache[iface.Type] = graphql.NewInterface(graphql.InterfaceConfig{
+			Name:        string(iface.Type),
+			Description: iface.Description,
+			Fields:      make(graphql.Fields),
+			ResolveType: h.resolveType(iface.ResolveType),
+		})
+	}
+
+	for _, union := range h.unions {
+		types := make([]*graphql.Object, 0, len(union.Types))
+		for _, t := range union.Types {
+			obj, ok := h.preCache[t].(*graphql.Object)
+			if !ok {
+				return fmt.Errorf("union %s: unsupported node type: %s", union.Type, t)
+			}
+			types = append(types, obj)
+		}
+		h.preCache[union.Type] = graphql.NewUnion(graphql.UnionConfig{
+			Name:        string(union.Type),
+			Description: union.Description,
+			Types:       types,
+			ResolveType: h.resolveType(union.ResolveType),
+		})
+	}
+	return nil
+}
+
+// buildAbstracts 转换 interface 的 fields.
+func (h *NodeRegistry) buildAbstracts() error {
+	for _, iface := range h.interfaces {
+		t := h.preCache[iface.Type].(*graphql.Interface)
+		for _, f := range iface.Fields {
+			field, err := f.Convert(h)
+			if err != nil {
+				return fmt.Errorf("interface %s: %w", iface.Type, err)
+			}
+			t.AddFieldConfig(f.fieldName, field)
+		}
+	}
+	return nil
+}
+
+// nodeInterfaces returns the interfaces implemented by the Node type, they are created by preLoadAbstracts,
+// and the Node interface created by preLoadNodeInterface.
+func (h *NodeRegistry) nodeInterfaces(flag FieldType) []*graphql.Interface {
+	interfaces := make([]*graphql.Interface, 0)
+	if _, ok := h.nodeFetcher(flag); ok {
+		if i, ok := h.preCache[NodeInterfaceName].(*graphql.Interface); ok {
+			interfaces = append(interfaces, i)
+		}
+	}
+	for _, iface := range h.interfaces {
+		for _, t := range iface.Types {
+			if t != flag {
+				continue
+			}
+			if i, ok := h.preCache[iface.Type].(*graphql.Interface); ok {
+				interfaces = append(interfaces, i)
+			}
+		}
+	}
+	return interfaces
+}
+
+// resolveType picks the object of a value by the resolver, or by the TypenameKey of the value.
+func (h *NodeRegistry) resolveType(resolver TypeResolver) graphql.ResolveTypeFn {
+	return func(p graphql.ResolveTypeParams) *graphql.Object {
+		var flag FieldType
+		ok := false
+		if resolver != nil {
+			flag, ok = resolver(p.Value)
+		}
+		if !ok {
+			row, isMap := p.Value.(map[string]interface{})
+			if !isMap {
+				return nil
+			}
+			switch t := row[TypenameKey].(type) {
+			case FieldType:
+				flag = t
+			case string:
+				flag = FieldType(t)
+			default:
+				return nil
+			}
+		}
+		obj, _ := h.preCache[flag].(*graphql.Object)
+		return obj
+	}
+}
+
+// outputType returns the interface or union of a PolymorphicNode, or the object of any other Node.
+func (h *NodeRegistry) outputType(delegate Node) (FieldType, error) {
+	node, ok := delegate.(PolymorphicNode)
+	if !ok || node.OutputType() == "" {
+		return delegate.Type(), nil
+	}
+	if !h.isAbstract(node.OutputType()) {
+		return "", fmt.Errorf("node %s: output type %s is not a registered interface or union", delegate.Type(), node.OutputType())
+	}
+	return node.OutputType(), nil
+}
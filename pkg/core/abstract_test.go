@@ -0,0 +1,44 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/graphql-go/graphql"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRegistry_ResolveType(t *testing.T) {
+	registry := NewRegistry()
+	registry.Register(&thingNode{})
+	registry.RegisterUnion(&Union{Type: "Anything", Types: []FieldType{"thing"}})
+	if _, err := registry.BuildHandler(); err != nil {
+		t.Fatalf("BuildHandler failed: %v", err)
+	}
+	thing, err := registry.GetObject("thing")
+	if err != nil {
+		t.Fatalf("GetObject failed: %v", err)
+	}
+
+	resolve := registry.resolveType(func(value interface{}) (FieldType, bool) {
+		name, ok := value.(string)
+		return FieldType(name), ok
+	})
+	tests := []struct {
+		name     string
+		value    interface{}
+		expected *graphql.Object
+	}{
+		{"resolver", "thing", thing},
+		{"resolver unknown type", "robot", nil},
+		{"typename", map[string]interface{}{TypenameKey: FieldType("thing")}, thing},
+		{"typename text", map[string]interface{}{TypenameKey: "thing"}, thing},
+		{"unknown typename", map[string]interface{}{TypenameKey: "robot"}, nil},
+		{"no typename", map[string]interface{}{}, nil},
+		{"no map", 1, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, resolve(graphql.ResolveTypeParams{Value: tt.value}))
+		})
+	}
+}
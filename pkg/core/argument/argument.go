@@ -19,28 +19,159 @@ type Argument interface {
 
 type Builder func() Argument
 
-var argumentBuilders = make(map[string]Builder)
+// DefaultSet is the shared default set of arguments, e.g. filter, limit and orderBy,
+// every registry starts with a copy of it, see core.NodeRegistry.Arguments.
+var DefaultSet = NewSet()
 
+// RegisterArgument adds an argument to DefaultSet, it should be called in init,
+// before any registry is created.
 func RegisterArgument(typename string, arg Builder) {
-	argumentBuilders[typename] = arg
+	DefaultSet.Register(typename, arg)
 }
 
-type DefaultArgumentBuilder struct {
+// Set is an ordered set of arguments identified by their typename,
+// the arguments are built in the order they are registered.
+type Set struct {
+	typenames []string
+	builders  map[string]Builder
 }
 
-func (i *DefaultArgumentBuilder) BuildArgs() []Argument {
-	res := make([]Argument, 0, len(argumentBuilders))
-	for _, builder := range argumentBuilders {
-		res = append(res, builder())
+func NewSet() *Set {
+	return &Set{builders: make(map[string]Builder)}
+}
+
+// Register adds an argument to the set, an argument of the same typename is replaced in place.
+func (s *Set) Register(typename string, builder Builder) *Set {
+	if _, ok := s.builders[typename]; !ok {
+		s.typenames = append(s.typenames, typename)
 	}
-	return res
+	s.builders[typename] = builder
+	return s
+}
+
+// Remove removes the arguments of the typenames from the set.
+func (s *Set) Remove(typenames ...string) *Set {
+	for _, typename := range typenames {
+		if _, ok := s.builders[typename]; !ok {
+			continue
+		}
+		delete(s.builders, typename)
+		for i, name := range s.typenames {
+			if name == typename {
+				s.typenames = append(s.typenames[:i], s.typenames[i+1:]...)
+				break
+			}
+		}
+	}
+	return s
+}
+
+// Clone returns a copy of the set, so it can be changed without affecting the set it is copied from.
+func (s *Set) Clone() *Set {
+	clone := NewSet()
+	for _, typename := range s.typenames {
+		clone.Register(typename, s.builders[typename])
+	}
+	return clone
+}
+
+// TypeNames returns the typenames of the arguments in the order they are registered.
+func (s *Set) TypeNames() []string {
+	return append([]string(nil), s.typenames...)
 }
 
 // Factory create a argument instance by typename, when typename is not exist, return nil.
-func Factory(typename string) Argument {
-	builder, ok := argumentBuilders[typename]
+func (s *Set) Factory(typename string) Argument {
+	builder, ok := s.builders[typename]
 	if ok {
 		return builder()
 	}
 	return nil
 }
+
+// Build creates an instance of every argument in the order they are registered.
+func (s *Set) Build() []Argument {
+	res := make([]Argument, 0, len(s.typenames))
+	for _, typename := range s.typenames {
+		res = append(res, s.builders[typename]())
+	}
+	return res
+}
+
+// DefaultArgumentBuilder picks the arguments of a Node from the arguments of its registry,
+// a Node embedding it can opt in or out of them individually, e.g.
+//
+//	d.ExcludeArguments(sqlArgument.LimitArgumentType)
+//	d.RegisterArgument("search", newSearchArgument)
+type DefaultArgumentBuilder struct {
+	// arguments 不为 nil 时替代 registry 的参数, 见 SetArguments.
+	arguments *Set
+	include   []string
+	exclude   []string
+	// extra 只属于当前 Node 的参数.
+	extra *Set
+}
+
+// SetArguments uses the set instead of the arguments of the registry, e.g. a set shared by several Nodes.
+func (i *DefaultArgumentBuilder) SetArguments(set *Set) {
+	i.arguments = set
+}
+
+// IncludeArguments only keeps the arguments of the typenames, the other arguments of the registry are left out.
+func (i *DefaultArgumentBuilder) IncludeArguments(typenames ...string) {
+	i.include = append(i.include, typenames...)
+}
+
+// ExcludeArguments leaves out the arguments of the typenames.
+func (i *DefaultArgumentBuilder) ExcludeArguments(typenames ...string) {
+	i.exclude = append(i.exclude, typenames...)
+}
+
+// RegisterArgument adds an argument to the Node only, it is not affected by IncludeArguments and ExcludeArguments.
+func (i *DefaultArgumentBuilder) RegisterArgument(typename string, builder Builder) {
+	if i.extra == nil {
+		i.extra = NewSet()
+	}
+	i.extra.Register(typename, builder)
+}
+
+// ArgumentSet implements core.ArgumentSetBuilder, it returns the arguments of the Node picked from defaults.
+func (i *DefaultArgumentBuilder) ArgumentSet(defaults *Set) *Set {
+	set := defaults
+	if i.arguments != nil {
+		set = i.arguments
+	}
+	set = set.Clone()
+
+	if len(i.include) > 0 {
+		included := make(map[string]struct{}, len(i.include))
+		for _, typename := range i.include {
+			included[typename] = struct{}{}
+		}
+		for _, typename := range set.TypeNames() {
+			if _, ok := included[typename]; !ok {
+				set.Remove(typename)
+			}
+		}
+	}
+	set.Remove(i.exclude...)
+
+	if i.extra != nil {
+		for _, typename := range i.extra.typenames {
+			set.Register(typename, i.extra.builders[typename])
+		}
+	}
+	return set
+}
+
+// BuildArgs returns the arguments of the Node picked from DefaultSet,
+// the registry picks them from its own arguments by ArgumentSet instead.
+func (i *DefaultArgumentBuilder) BuildArgs() []Argument {
+	return i.ArgumentSet(DefaultSet).Build()
+}
+
+// Factory create a argument instance of DefaultSet by typename, when typename is not exist, return nil.
+// The arguments of a Node are created by core.NodeRegistry.Argument.
+func Factory(typename string) Argument {
+	return DefaultSet.Factory(typename)
+}
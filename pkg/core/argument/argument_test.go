@@ -0,0 +1,132 @@
+package argument
+
+import (
+	"testing"
+
+	"github.com/graphql-go/graphql"
+	"github.com/stretchr/testify/assert"
+)
+
+type stubArgument struct {
+	typename string
+	version  int
+}
+
+func (a *stubArgument) TypeName() string               { return a.typename }
+func (a *stubArgument) Validate(interface{}) error     { return nil }
+func (a *stubArgument) GetArgumentType() graphql.Input { return graphql.String }
+
+func stubBuilder(typename string, version int) Builder {
+	return func() Argument { return &stubArgument{typename: typename, version: version} }
+}
+
+func newStubSet(typenames ...string) *Set {
+	set := NewSet()
+	for _, typename := range typenames {
+		set.Register(typename, stubBuilder(typename, 1))
+	}
+	return set
+}
+
+func typeNames(args []Argument) []string {
+	names := make([]string, len(args))
+	for i, arg := range args {
+		names[i] = arg.TypeName()
+	}
+	return names
+}
+
+func TestSet(t *testing.T) {
+	set := newStubSet("filter", "limit", "orderBy")
+	assert.Equal(t, []string{"filter", "limit", "orderBy"}, set.TypeNames())
+	assert.Equal(t, []string{"filter", "limit", "orderBy"}, typeNames(set.Build()))
+
+	// an argument of the same typename is replaced in place.
+	set.Register("limit", stubBuilder("limit", 2))
+	assert.Equal(t, []string{"filter", "limit", "orderBy"}, set.TypeNames())
+	assert.Equal(t, 2, set.Factory("limit").(*stubArgument).version)
+
+	set.Remove("limit", "unknown")
+	assert.Equal(t, []string{"filter", "orderBy"}, set.TypeNames())
+	assert.Nil(t, set.Factory("limit"))
+
+	// every call creates a new instance.
+	assert.NotSame(t, set.Factory("filter"), set.Factory("filter"))
+}
+
+func TestSet_Clone(t *testing.T) {
+	set := newStubSet("filter", "limit")
+	clone := set.Clone()
+	clone.Remove("filter")
+	clone.Register("search", stubBuilder("search", 1))
+
+	assert.Equal(t, []string{"filter", "limit"}, set.TypeNames())
+	assert.Equal(t, []string{"limit", "search"}, clone.TypeNames())
+
+	// the typenames returned are a copy.
+	names := set.TypeNames()
+	names[0] = "changed"
+	assert.Equal(t, []string{"filter", "limit"}, set.TypeNames())
+}
+
+func TestDefaultArgumentBuilder_ArgumentSet(t *testing.T) {
+	tests := []struct {
+		name      string
+		configure func(b *DefaultArgumentBuilder)
+		expected  []string
+	}{
+		{
+			name:      "defaults",
+			configure: func(*DefaultArgumentBuilder) {},
+			expected:  []string{"filter", "limit", "orderBy"},
+		},
+		{
+			name: "include",
+			configure: func(b *DefaultArgumentBuilder) {
+				b.IncludeArguments("orderBy", "filter", "unknown")
+			},
+			expected: []string{"filter", "orderBy"},
+		},
+		{
+			name: "exclude",
+			configure: func(b *DefaultArgumentBuilder) {
+				b.ExcludeArguments("limit")
+			},
+			expected: []string{"filter", "orderBy"},
+		},
+		{
+			name: "include and exclude",
+			configure: func(b *DefaultArgumentBuilder) {
+				b.IncludeArguments("filter", "limit")
+				b.ExcludeArguments("filter")
+			},
+			expected: []string{"limit"},
+		},
+		{
+			name: "extra",
+			configure: func(b *DefaultArgumentBuilder) {
+				b.IncludeArguments("filter")
+				b.RegisterArgument("search", stubBuilder("search", 1))
+			},
+			expected: []string{"filter", "search"},
+		},
+		{
+			name: "set",
+			configure: func(b *DefaultArgumentBuilder) {
+				b.SetArguments(newStubSet("limit", "search"))
+				b.ExcludeArguments("limit")
+			},
+			expected: []string{"search"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defaults := newStubSet("filter", "limit", "orderBy")
+			b := &DefaultArgumentBuilder{}
+			tt.configure(b)
+			assert.Equal(t, tt.expected, b.ArgumentSet(defaults).TypeNames())
+			// the defaults are left unchanged.
+			assert.Equal(t, []string{"filter", "limit", "orderBy"}, defaults.TypeNames())
+		})
+	}
+}
@@ -0,0 +1,58 @@
+package core
+
+import (
+	"context"
+	"database/sql"
+	"math/rand"
+	"sync/atomic"
+)
+
+// Balancer picks the replica a query is sent to, see DataSource.SetBalancer.
+// replicas is never empty.
+type Balancer interface {
+	Pick(ctx context.Context, replicas []*sql.DB) *sql.DB
+}
+
+// BalancerFunc adapts a function to a Balancer.
+type BalancerFunc func(ctx context.Context, replicas []*sql.DB) *sql.DB
+
+func (f BalancerFunc) Pick(ctx context.Context, replicas []*sql.DB) *sql.DB {
+	return f(ctx, replicas)
+}
+
+// NewRoundRobinBalancer returns a Balancer picking the replicas in turn, it is the default Balancer.
+func NewRoundRobinBalancer() Balancer {
+	var next uint64
+	return BalancerFunc(func(_ context.Context, replicas []*sql.DB) *sql.DB {
+		n := atomic.AddUint64(&next, 1) - 1
+		return replicas[n%uint64(len(replicas))]
+	})
+}
+
+// NewRandomBalancer returns a Balancer picking a random replica.
+func NewRandomBalancer() Balancer {
+	return BalancerFunc(func(_ context.Context, replicas []*sql.DB) *sql.DB {
+		return replicas[rand.Intn(len(replicas))]
+	})
+}
+
+type readPrimaryKey struct{}
+
+// WithReadPrimary makes the queries of the request read from the primary database instead of the replicas,
+// so a client can read its own writes right after a mutation, e.g. in an HTTP middleware:
+//
+//	if r.Header.Get("X-Read-Your-Writes") != "" {
+//		r = r.WithContext(core.WithReadPrimary(r.Context()))
+//	}
+func WithReadPrimary(ctx context.Context) context.Context {
+	return context.WithValue(ctx, readPrimaryKey{}, true)
+}
+
+// IsReadPrimary reports whether the queries of the context read from the primary database.
+func IsReadPrimary(ctx context.Context) bool {
+	if ctx == nil {
+		return false
+	}
+	readPrimary, _ := ctx.Value(readPrimaryKey{}).(bool)
+	return readPrimary
+}
@@ -0,0 +1,129 @@
+package core
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/Finovate/go-gql-builder/pkg/dialect"
+)
+
+// DefaultDataSource is the name of the data source set by NodeRegistry.SetDB,
+// Nodes which are not bound to a data source use it.
+const DefaultDataSource = "default"
+
+// DataSource is a named database of a NodeRegistry, see NodeRegistry.RegisterDataSource.
+// It is made of a primary database and optional replicas, the queries are sent to a replica picked by the Balancer,
+// while mutations, the queries of a mutation operation and the requests marked by WithReadPrimary use the primary.
+type DataSource struct {
+	name     string
+	db       *sql.DB
+	replicas []*sql.DB
+	balancer Balancer
+	dialect  dialect.Dialect
+}
+
+func (s *DataSource) Name() string {
+	return s.name
+}
+
+// DB returns the primary database.
+func (s *DataSource) DB() *sql.DB {
+	return s.db
+}
+
+// Replicas returns the read-only replicas of the primary database.
+func (s *DataSource) Replicas() []*sql.DB {
+	return s.replicas
+}
+
+// AddReplica adds read-only replicas of the primary database.
+func (s *DataSource) AddReplica(replicas ...*sql.DB) *DataSource {
+	s.replicas = append(s.replicas, replicas...)
+	return s
+}
+
+// SetBalancer sets the policy picking the replica of a query, NewRoundRobinBalancer is used by default.
+func (s *DataSource) SetBalancer(balancer Balancer) *DataSource {
+	if balancer == nil {
+		balancer = NewRoundRobinBalancer()
+	}
+	s.balancer = balancer
+	return s
+}
+
+// replica returns the database a query is read from, the primary is used when there is no replica.
+func (s *DataSource) replica(ctx context.Context) *sql.DB {
+	if len(s.replicas) == 0 || IsReadPrimary(ctx) {
+		return s.db
+	}
+	if db := s.balancer.Pick(ctx, s.replicas); db != nil {
+		return db
+	}
+	return s.db
+}
+
+// Dialect returns the SQL dialect of the data source, it is nil when the dialect of the registry applies.
+func (s *DataSource) Dialect() dialect.Dialect {
+	return s.dialect
+}
+
+// SetDialect selects the SQL dialect of the data source, it overrides the dialect of the registry.
+func (s *DataSource) SetDialect(d dialect.Dialect) *DataSource {
+	s.dialect = d
+	return s
+}
+
+// DataSourceBinder is optionally implemented by a Node bound to a named data source,
+// the registry checks that the data source is registered when the schema is built.
+// An empty name means DefaultDataSource.
+type DataSourceBinder interface {
+	DataSourceName() string
+}
+
+// RegisterDataSource adds a named database to the registry, it replaces the data source of the same name,
+// e.g. h.RegisterDataSource("crm", primary).AddReplica(replica1, replica2).
+func (h *NodeRegistry) RegisterDataSource(name string, db *sql.DB) *DataSource {
+	source := &DataSource{name: name, db: db, balancer: NewRoundRobinBalancer()}
+	h.dataSources[name] = source
+	return source
+}
+
+// GetDataSource returns the data source of the name, an empty name means DefaultDataSource.
+func (h *NodeRegistry) GetDataSource(name string) (*DataSource, error) {
+	if name == "" {
+		name = DefaultDataSource
+	}
+	source, ok := h.dataSources[name]
+	if !ok || source.db == nil {
+		return nil, fmt.Errorf("unknown data source: %s", name)
+	}
+	return source, nil
+}
+
+// DataSourceDialect returns the dialect of the data source, or the dialect of the registry
+// when the data source has none.
+func (h *NodeRegistry) DataSourceDialect(name string) dialect.Dialect {
+	if source, err := h.GetDataSource(name); err == nil && source.dialect != nil {
+		return source.dialect
+	}
+	return h.GetDialect()
+}
+
+// validateDataSources 检查所有 node 绑定的数据源都已注册, 未指定数据源的 node 使用默认数据源, 允许在构建之后再 SetDB.
+func (h *NodeRegistry) validateDataSources() error {
+	for _, delegate := range h.nodes {
+		binder, ok := delegate.(DataSourceBinder)
+		if !ok {
+			continue
+		}
+		name := binder.DataSourceName()
+		if name == "" || name == DefaultDataSource {
+			continue
+		}
+		if _, err := h.GetDataSource(name); err != nil {
+			return fmt.Errorf("node %s is bound to an unknown data source: %s", delegate.Name(), name)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,121 @@
+package core
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/graphql-go/graphql"
+	"github.com/stretchr/testify/assert"
+)
+
+// newStubDBs returns a database recording its statements in log for every name.
+func newStubDBs(t *testing.T, log *stubLog, names ...string) []*sql.DB {
+	dbs := make([]*sql.DB, len(names))
+	for i, name := range names {
+		db := sql.OpenDB(&stubDB{name: name, log: log})
+		t.Cleanup(func() { _ = db.Close() })
+		dbs[i] = db
+	}
+	return dbs
+}
+
+func TestRoundRobinBalancer(t *testing.T) {
+	replicas := newStubDBs(t, &stubLog{}, "a", "b", "c")
+	balancer := NewRoundRobinBalancer()
+
+	for i := 0; i < 7; i++ {
+		assert.Samef(t, replicas[i%3], balancer.Pick(context.Background(), replicas), "Pick() failed for query %d", i)
+	}
+}
+
+func TestRandomBalancer(t *testing.T) {
+	replicas := newStubDBs(t, &stubLog{}, "a", "b")
+	balancer := NewRandomBalancer()
+
+	for i := 0; i < 10; i++ {
+		assert.Contains(t, replicas, balancer.Pick(context.Background(), replicas))
+	}
+}
+
+func TestReadQuerier(t *testing.T) {
+	dbs := newStubDBs(t, &stubLog{}, "primary", "a", "b")
+	primary, replicas := dbs[0], dbs[1:]
+	registry := NewRegistry()
+	source := registry.RegisterDataSource(DefaultDataSource, primary)
+	ctx := context.Background()
+
+	read := func(ctx context.Context) Querier {
+		t.Helper()
+		querier, err := registry.ReadQuerier(ctx, "")
+		if err != nil {
+			t.Fatalf("ReadQuerier failed: %v", err)
+		}
+		return querier
+	}
+
+	// a data source without replica reads from the primary.
+	assert.Same(t, primary, read(ctx))
+
+	source.AddReplica(replicas...)
+	assert.Same(t, replicas[0], read(ctx))
+	assert.Same(t, replicas[1], read(ctx))
+	assert.Same(t, replicas[0], read(ctx))
+	assert.Same(t, primary, read(WithReadPrimary(ctx)))
+	assert.False(t, IsReadPrimary(ctx))
+	assert.True(t, IsReadPrimary(WithReadPrimary(ctx)))
+
+	source.SetBalancer(BalancerFunc(func(_ context.Context, replicas []*sql.DB) *sql.DB {
+		return replicas[1]
+	}))
+	assert.Same(t, replicas[1], read(ctx))
+	// the primary is read when the balancer picks no replica.
+	source.SetBalancer(BalancerFunc(func(context.Context, []*sql.DB) *sql.DB { return nil }))
+	assert.Same(t, primary, read(ctx))
+
+	_, err := registry.ReadQuerier(ctx, "crm")
+	assert.EqualError(t, err, "unknown data source: crm")
+}
+
+func TestReadQuerier_Mutation(t *testing.T) {
+	registry, log, _ := newTransactionRegistry(t)
+	source, err := registry.GetDataSource("")
+	if err != nil {
+		t.Fatalf("GetDataSource failed: %v", err)
+	}
+	source.AddReplica(newStubDBs(t, log, "replica")...)
+	registry.RegisterMutation("read", &graphql.Field{
+		Type: graphql.String,
+		Args: graphql.FieldConfigArgument{
+			"statement": &graphql.ArgumentConfig{Type: graphql.String},
+		},
+		Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+			statement, _ := p.Args["statement"].(string)
+			querier, err := registry.ReadQuerier(p.Context, "")
+			if err != nil {
+				return nil, err
+			}
+			rows, err := querier.QueryContext(p.Context, statement)
+			if err != nil {
+				return nil, err
+			}
+			_ = rows.Close()
+			return statement, nil
+		},
+	})
+
+	// the reads of a mutation see its writes, they are sent to its transaction instead of a replica.
+	_, errs := executeQuery(t, registry, `mutation {
+		a: exec(statement: "INSERT a")
+		b: read(statement: "SELECT b")
+	}`)
+	assert.Empty(t, errs)
+	assert.Equal(t, []string{"default: BEGIN", "default: INSERT a", "default: SELECT b", "default: COMMIT"}, log.Statements())
+
+	// the reads outside a mutation are sent to the replica.
+	querier, err := registry.ReadQuerier(context.Background(), "")
+	assert.NoError(t, err)
+	_, err = querier.ExecContext(context.Background(), "SELECT c")
+	assert.NoError(t, err)
+	assert.Equal(t, "replica: SELECT c", log.Statements()[4])
+}
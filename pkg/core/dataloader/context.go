@@ -0,0 +1,65 @@
+package dataloader
+
+import (
+	"context"
+	"sync"
+)
+
+type contextKey struct{}
+
+// Loaders holds the Loaders of one request, they are created on demand by name.
+type Loaders struct {
+	lock    sync.Mutex
+	loaders map[string]*Loader
+}
+
+func NewLoaders() *Loaders {
+	return &Loaders{
+		loaders: make(map[string]*Loader),
+	}
+}
+
+// Get returns the Loader registered under name, batchFn is used to create it when it does not exist yet.
+func (ls *Loaders) Get(name string, batchFn BatchFunc) *Loader {
+	ls.lock.Lock()
+	defer ls.lock.Unlock()
+
+	loader, ok := ls.loaders[name]
+	if !ok {
+		loader = NewLoader(batchFn)
+		ls.loaders[name] = loader
+	}
+	return loader
+}
+
+// NewContext returns a context carrying a new set of Loaders, it should be created once per request.
+func NewContext(ctx context.Context) context.Context {
+	return context.WithValue(ctx, contextKey{}, NewLoaders())
+}
+
+// FromContext returns the Loaders of the request, nil when the context does not carry them.
+func FromContext(ctx context.Context) *Loaders {
+	if ctx == nil {
+		return nil
+	}
+	loaders, _ := ctx.Value(contextKey{}).(*Loaders)
+	return loaders
+}
+
+// Load loads key with the Loader registered under name in the request context and returns a thunk
+// which can be returned by a resolver directly, e.g.
+//
+//	field.SetResolver(func(p graphql.ResolveParams) (interface{}, error) {
+//		userID := p.Source.(map[string]interface{})["id"]
+//		return dataloader.Load(p.Context, "user.avatar", userID, loadAvatars), nil
+//	})
+//
+// name must identify the data being loaded, the keys of every resolver using the same name are loaded together.
+// When the context does not carry any Loaders, the key is loaded on its own.
+func Load(ctx context.Context, name string, key interface{}, batchFn BatchFunc) func() (interface{}, error) {
+	loaders := FromContext(ctx)
+	if loaders == nil {
+		return NewLoader(batchFn).Load(ctx, key)
+	}
+	return loaders.Get(name, batchFn).Load(ctx, key)
+}
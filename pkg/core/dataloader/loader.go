@@ -0,0 +1,95 @@
+package dataloader
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// BatchFunc loads the values of several keys in a single round trip,
+// the returned values must be in the same order as keys.
+type BatchFunc func(ctx context.Context, keys []interface{}) ([]interface{}, error)
+
+// Loader collects the keys requested by the resolvers of one execution level and loads them with a single BatchFunc call.
+// Load returns a thunk which is supported by graphql-go as the result of a resolver,
+// graphql-go calls the thunks after all the fields of a level are resolved,
+// so the first call of a thunk loads every key collected until then.
+type Loader struct {
+	batchFn BatchFunc
+
+	lock    sync.Mutex
+	current *batch
+	// batches caches the batch of every loaded key, a key is only loaded once per request.
+	batches map[interface{}]*batch
+}
+
+func NewLoader(batchFn BatchFunc) *Loader {
+	return &Loader{
+		batchFn: batchFn,
+		batches: make(map[interface{}]*batch),
+	}
+}
+
+// Load adds the key to the current batch and returns a thunk resolving its value, key must be comparable.
+func (l *Loader) Load(ctx context.Context, key interface{}) func() (interface{}, error) {
+	l.lock.Lock()
+	b, ok := l.batches[key]
+	if !ok {
+		if l.current == nil {
+			l.current = newBatch()
+		}
+		b = l.current
+		b.add(key)
+		l.batches[key] = b
+	}
+	l.lock.Unlock()
+
+	return func() (interface{}, error) {
+		l.dispatch(ctx, b)
+		return b.get(key)
+	}
+}
+
+func (l *Loader) dispatch(ctx context.Context, b *batch) {
+	l.lock.Lock()
+	if l.current == b {
+		// the keys added from now on belong to a new batch.
+		l.current = nil
+	}
+	l.lock.Unlock()
+
+	b.once.Do(func() {
+		b.values, b.err = l.batchFn(ctx, b.keys)
+		if b.err == nil && len(b.values) != len(b.keys) {
+			b.err = fmt.Errorf("batch function returned %d values for %d keys", len(b.values), len(b.keys))
+		}
+	})
+}
+
+type batch struct {
+	keys  []interface{}
+	index map[interface{}]int
+
+	once   sync.Once
+	values []interface{}
+	err    error
+}
+
+func newBatch() *batch {
+	return &batch{
+		keys:  make([]interface{}, 0),
+		index: make(map[interface{}]int),
+	}
+}
+
+func (b *batch) add(key interface{}) {
+	b.index[key] = len(b.keys)
+	b.keys = append(b.keys, key)
+}
+
+func (b *batch) get(key interface{}) (interface{}, error) {
+	if b.err != nil {
+		return nil, b.err
+	}
+	return b.values[b.index[key]], nil
+}
@@ -0,0 +1,52 @@
+package dataloader
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoader_Batch(t *testing.T) {
+	calls := make([][]interface{}, 0)
+	batchFn := func(ctx context.Context, keys []interface{}) ([]interface{}, error) {
+		calls = append(calls, keys)
+		values := make([]interface{}, len(keys))
+		for i, key := range keys {
+			values[i] = key.(int) * 10
+		}
+		return values, nil
+	}
+
+	ctx := NewContext(context.Background())
+	thunks := []func() (interface{}, error){
+		Load(ctx, "test", 1, batchFn),
+		Load(ctx, "test", 2, batchFn),
+		Load(ctx, "test", 1, batchFn),
+	}
+
+	for i, expected := range []int{10, 20, 10} {
+		value, err := thunks[i]()
+		assert.NoError(t, err)
+		assert.Equal(t, expected, value)
+	}
+	assert.Equal(t, [][]interface{}{{1, 2}}, calls)
+
+	// the keys added after the dispatch belong to a new batch, the loaded keys are cached.
+	thunk := Load(ctx, "test", 3, batchFn)
+	value, err := Load(ctx, "test", 2, batchFn)()
+	assert.NoError(t, err)
+	assert.Equal(t, 20, value)
+	value, err = thunk()
+	assert.NoError(t, err)
+	assert.Equal(t, 30, value)
+	assert.Equal(t, [][]interface{}{{1, 2}, {3}}, calls)
+}
+
+func TestLoader_WithoutContext(t *testing.T) {
+	value, err := Load(context.Background(), "test", 1, func(ctx context.Context, keys []interface{}) ([]interface{}, error) {
+		return []interface{}{"one"}, nil
+	})()
+	assert.NoError(t, err)
+	assert.Equal(t, "one", value)
+}
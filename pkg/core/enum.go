@@ -0,0 +1,61 @@
+package core
+
+import (
+	"github.com/graphql-go/graphql"
+)
+
+// Enum declares an enum FieldType, the FieldType is the name of the GraphQL enum, e.g. UserStatus.
+type Enum struct {
+	Type        FieldType
+	Description string
+	Values      []*EnumValue
+}
+
+// EnumValue is a value of an Enum.
+type EnumValue struct {
+	// Name is the GraphQL name of the value, e.g. ACTIVE.
+	Name string
+	// Value is the value read from and written to the database, Name is used when it is nil.
+	Value             interface{}
+	Description       string
+	DeprecationReason string
+}
+
+// EnumBuilder is optionally implemented by a Node declaring the enums used by its fields and columns,
+// e.g. the Nodes generated from tables with ENUM columns. They are registered with the Node, see NodeRegistry.RegisterEnum.
+type EnumBuilder interface {
+	BuildEnums() []*Enum
+}
+
+// RegisterEnum declares an enum FieldType of this registry, e.g.
+//
+//	registry.RegisterEnum(&core.Enum{
+//		Type: "UserStatus",
+//		Values: []*core.EnumValue{
+//			{Name: "ACTIVE", Value: "active", Description: "the user can sign in"},
+//			{Name: "DISABLED", Value: "disabled"},
+//		},
+//	})
+//
+// Like the types registered with RegisterScalar, it can be used by fields and columns,
+// which then only accept and return the declared values.
+func (h *NodeRegistry) RegisterEnum(enum *Enum) {
+	values := make(graphql.EnumValueConfigMap, len(enum.Values))
+	for _, value := range enum.Values {
+		v := value.Value
+		if v == nil {
+			v = value.Name
+		}
+		values[value.Name] = &graphql.EnumValueConfig{
+			Value:             v,
+			Description:       value.Description,
+			DeprecationReason: value.DeprecationReason,
+		}
+	}
+
+	h.RegisterScalar(enum.Type, graphql.NewEnum(graphql.EnumConfig{
+		Name:        string(enum.Type),
+		Description: enum.Description,
+		Values:      values,
+	}))
+}
@@ -0,0 +1,60 @@
+package core
+
+import (
+	"context"
+	"errors"
+
+	"github.com/graphql-go/graphql/gqlerrors"
+)
+
+// The codes of Error, reported in the extensions of the GraphQL errors.
+const (
+	// ErrorCodeTimeout the statement exceeded the query timeout, see NodeRegistry.SetQueryTimeout.
+	ErrorCodeTimeout = "QUERY_TIMEOUT"
+	// ErrorCodeCanceled the request was canceled while the statement was running, e.g. the client disconnected.
+	ErrorCodeCanceled = "QUERY_CANCELED"
+)
+
+var _ gqlerrors.ExtendedError = (*Error)(nil)
+
+// Error is a resolver error with a code, the code is reported in the extensions of the GraphQL error,
+// e.g. {"message": "query timeout exceeded", "extensions": {"code": "QUERY_TIMEOUT"}}.
+// graphql-go only recognizes it when it is returned by the resolver as it is, without being wrapped.
+type Error struct {
+	Code    string
+	Message string
+	Err     error
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+func (e *Error) Extensions() map[string]interface{} {
+	return map[string]interface{}{"code": e.Code}
+}
+
+// ContextError converts the error of a statement interrupted by its context into an Error
+// with ErrorCodeTimeout or ErrorCodeCanceled, the other errors are returned as they are.
+func ContextError(ctx context.Context, err error) error {
+	if err == nil {
+		return nil
+	}
+	var codeErr *Error
+	if errors.As(err, &codeErr) {
+		return err
+	}
+
+	switch ctx.Err() {
+	case context.DeadlineExceeded:
+		return &Error{Code: ErrorCodeTimeout, Message: "query timeout exceeded", Err: err}
+	case context.Canceled:
+		return &Error{Code: ErrorCodeCanceled, Message: "query canceled", Err: err}
+	default:
+		return err
+	}
+}
@@ -0,0 +1,33 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestContextError(t *testing.T) {
+	failure := errors.New("driver failure")
+
+	timeout, cancel := context.WithTimeout(context.Background(), time.Nanosecond)
+	defer cancel()
+	<-timeout.Done()
+	canceled, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	err := ContextError(timeout, failure)
+	assert.Equal(t, &Error{Code: ErrorCodeTimeout, Message: "query timeout exceeded", Err: failure}, err)
+	assert.True(t, errors.Is(err, failure))
+	assert.Equal(t, map[string]interface{}{"code": ErrorCodeTimeout}, err.(*Error).Extensions())
+
+	// a canceled request is not reported as a timeout.
+	assert.Equal(t, &Error{Code: ErrorCodeCanceled, Message: "query canceled", Err: failure}, ContextError(canceled, failure))
+
+	// the error of a live context and an Error are returned as they are.
+	assert.Same(t, failure, ContextError(context.Background(), failure))
+	assert.Same(t, err, ContextError(canceled, err))
+	assert.NoError(t, ContextError(timeout, nil))
+}
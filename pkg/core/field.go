@@ -13,6 +13,13 @@ type Field struct {
 	fieldType FieldType
 
 	asList bool
+	// listDeclared reports whether the field declares itself as a list or not by SetAsList or List,
+	// otherwise a field referencing a Node follows Node.IsList.
+	listDeclared bool
+	// wrappers 由内向外包装字段类型, 例如 [String!]! 为 nonNull, list, nonNull.
+	wrappers []typeWrapper
+	// arguments 声明的参数, 见 AddArgument.
+	arguments []*FieldArgument
 
 	resolver graphql.FieldResolveFn
 }
@@ -32,10 +39,16 @@ func (f *Field) Convert(hub *NodeRegistry) (field *graphql.Field, err error) {
 		Resolve: f.resolver,
 	}
 
+	wrappers := f.wrappers
+	if f.asList {
+		wrappers = append([]typeWrapper{wrapList}, wrappers...)
+	}
+
+	var nodeArgs graphql.FieldConfigArgument
 	// 当field的类型是默认类型时
-	if !isDefault {
+	if !isDefault && !hub.isAbstract(f.fieldType) {
 		// When the field type is a custom Node type, recursively initialize the Node.
-		node, err := hub.getNode(f.fieldType)
+		node, err := hub.GetNode(f.fieldType)
 		if err != nil {
 			return nil, err
 		}
@@ -43,13 +56,60 @@ func (f *Field) Convert(hub *NodeRegistry) (field *graphql.Field, err error) {
 		if err = hub.buildNode(node); err != nil {
 			return nil, err
 		}
+		// the rows of a PolymorphicNode are of the types of its interface or union.
+		outputType, err := hub.outputType(node)
+		if err != nil {
+			return nil, err
+		}
+		t = hub.preCache[outputType]
 
-		field.Args = hub.argsMap[node.Type()]
+		if !f.listDeclared && node.IsList() {
+			wrappers = append([]typeWrapper{wrapList}, wrappers...)
+		}
+		// a single object can not be filtered, the arguments of the Node only apply to lists.
+		if containsList(wrappers) {
+			nodeArgs = hub.argsMap[node.Type()]
+		}
+	}
+	if field.Args, err = f.convertArguments(hub, nodeArgs); err != nil {
+		return nil, err
 	}
 
+	field.Type = wrapType(t, wrappers)
 	return field, nil
 }
 
+type typeWrapper int
+
+const (
+	wrapList typeWrapper = iota
+	wrapNonNull
+)
+
+// wrapType wraps t with the wrappers from the inside out.
+func wrapType(t graphql.Output, wrappers []typeWrapper) graphql.Output {
+	for _, wrapper := range wrappers {
+		switch wrapper {
+		case wrapList:
+			t = graphql.NewList(t)
+		case wrapNonNull:
+			if _, ok := t.(*graphql.NonNull); !ok {
+				t = graphql.NewNonNull(t)
+			}
+		}
+	}
+	return t
+}
+
+func containsList(wrappers []typeWrapper) bool {
+	for _, wrapper := range wrappers {
+		if wrapper == wrapList {
+			return true
+		}
+	}
+	return false
+}
+
 func (f *Field) SetResolver(resolver graphql.FieldResolveFn) {
 	f.resolver = resolver
 }
@@ -58,6 +118,30 @@ func (f *Field) Resolver() graphql.FieldResolveFn {
 	return f.resolver
 }
 
+// SetAsList declares whether the field is a list of its type.
+// By default, a field referencing a Node is a list when Node.IsList, any other field is a single value.
+func (f *Field) SetAsList(asList bool) {
+	f.asList = asList
+	f.listDeclared = true
+}
+
+// NonNull marks the type declared so far as non-null, e.g. String -> String!, [String] -> [String]!.
+// A field referencing a list Node without declaring List becomes [Node]!.
+func (f *Field) NonNull() *Field {
+	f.wrappers = append(f.wrappers, wrapNonNull)
+	return f
+}
+
+// List wraps the type declared so far in a list, the wrappers nest in the order they are called,
+// e.g. NewNodeField("tags", FieldTypeString).NonNull().List().NonNull() is [String!]!,
+// and List().List() is [[String]].
+// A field referencing a Node declaring List no longer follows Node.IsList.
+func (f *Field) List() *Field {
+	f.wrappers = append(f.wrappers, wrapList)
+	f.listDeclared = true
+	return f
+}
+
 func NewNodeField(fieldName string, fieldType FieldType) *Field {
 	return &Field{
 		fieldName: fieldName,
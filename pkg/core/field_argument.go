@@ -0,0 +1,97 @@
+package core
+
+import (
+	"fmt"
+
+	"github.com/graphql-go/graphql"
+)
+
+// FieldArgument is an argument declared on a Field, e.g. avatarUrl(size: Int = 64).
+// The values supplied by the client, or the default values, are passed to the resolver of the field in p.Args.
+type FieldArgument struct {
+	name         string
+	argType      FieldType
+	wrappers     []typeWrapper
+	defaultValue interface{}
+	description  string
+}
+
+// AddArgument declares an argument of the field, its type is a scalar or an enum FieldType,
+// including the types registered with NodeRegistry.RegisterScalar and NodeRegistry.RegisterEnum, e.g.
+//
+//	field := core.NewNodeField("avatarUrl", core.FieldTypeString)
+//	field.AddArgument("size", core.FieldTypeInt).SetDefault(64).SetDescription("the width in pixels")
+//	field.SetResolver(func(p graphql.ResolveParams) (interface{}, error) {
+//		size := p.Args["size"].(int)
+//		...
+//	})
+//
+// The arguments of a field referencing a Node are added to the arguments of the Node, e.g. filter and limit.
+func (f *Field) AddArgument(name string, argType FieldType) *FieldArgument {
+	arg := &FieldArgument{
+		name:    name,
+		argType: argType,
+	}
+	f.arguments = append(f.arguments, arg)
+	return arg
+}
+
+// IsFieldArgument reports whether the argument of the field being resolved is declared by Field.AddArgument,
+// such arguments are left to the resolver of the field by the Node.
+func (h *NodeRegistry) IsFieldArgument(info graphql.ResolveInfo, name string) bool {
+	if info.ParentType == nil {
+		return false
+	}
+	_, ok := h.fieldArguments[info.ParentType.Name()+"."+info.FieldName][name]
+	return ok
+}
+
+// NonNull marks the type declared so far as non-null, see Field.NonNull.
+func (a *FieldArgument) NonNull() *FieldArgument {
+	a.wrappers = append(a.wrappers, wrapNonNull)
+	return a
+}
+
+// List wraps the type declared so far in a list, see Field.List.
+func (a *FieldArgument) List() *FieldArgument {
+	a.wrappers = append(a.wrappers, wrapList)
+	return a
+}
+
+// SetDefault sets the value used when the client omits the argument.
+func (a *FieldArgument) SetDefault(value interface{}) *FieldArgument {
+	a.defaultValue = value
+	return a
+}
+
+func (a *FieldArgument) SetDescription(description string) *FieldArgument {
+	a.description = description
+	return a
+}
+
+// convertArguments 转换 field 声明的参数, 与 Node 的参数合并, 不能重名.
+func (f *Field) convertArguments(hub *NodeRegistry, nodeArgs graphql.FieldConfigArgument) (graphql.FieldConfigArgument, error) {
+	if len(f.arguments) == 0 {
+		return nodeArgs, nil
+	}
+
+	args := make(graphql.FieldConfigArgument, len(nodeArgs)+len(f.arguments))
+	for name, arg := range nodeArgs {
+		args[name] = arg
+	}
+	for _, arg := range f.arguments {
+		if _, ok := args[arg.name]; ok {
+			return nil, fmt.Errorf("field %s: duplicate argument: %s", f.fieldName, arg.name)
+		}
+		scalar, _ := hub.ScalarType(arg.argType)
+		if _, ok := scalar.(graphql.Input); !ok {
+			return nil, fmt.Errorf("field %s: unsupported argument type: %s", f.fieldName, arg.argType)
+		}
+		args[arg.name] = &graphql.ArgumentConfig{
+			Type:         wrapType(scalar, arg.wrappers).(graphql.Input),
+			DefaultValue: arg.defaultValue,
+			Description:  arg.description,
+		}
+	}
+	return args, nil
+}
@@ -0,0 +1,55 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// fieldsNode is a Node of the fields of a test, thing is a list Node.
+type fieldsNode struct {
+	thingNode
+	fields []*Field
+}
+
+func (n *fieldsNode) Name() string          { return "holders" }
+func (n *fieldsNode) Type() FieldType       { return "holder" }
+func (n *fieldsNode) BuildFields() []*Field { return n.fields }
+
+func TestField_Wrappers(t *testing.T) {
+	single := NewNodeField("single", "thing")
+	single.SetAsList(false)
+	single.NonNull()
+	node := &fieldsNode{fields: []*Field{
+		NewNodeField("tags", FieldTypeString).NonNull().List().NonNull(),
+		NewNodeField("matrix", FieldTypeInt).List().List(),
+		NewNodeField("twice", FieldTypeString).NonNull().NonNull(),
+		// the wrappers of a list Node apply to its list.
+		NewNodeField("things", "thing").NonNull(),
+		NewNodeField("declared", "thing").NonNull().List(),
+		single,
+	}}
+	registry := NewRegistry()
+	registry.Register(&thingNode{})
+	registry.Register(node)
+	if _, err := registry.BuildHandler(); err != nil {
+		t.Fatalf("BuildHandler failed: %v", err)
+	}
+	object, err := registry.GetObject("holder")
+	if err != nil {
+		t.Fatalf("GetObject failed: %v", err)
+	}
+
+	tests := map[string]string{
+		"tags":     "[String!]!",
+		"matrix":   "[[Int]]",
+		"twice":    "String!",
+		"things":   "[things]!",
+		"declared": "[things!]",
+		"single":   "things!",
+	}
+	fields := object.Fields()
+	for name, expected := range tests {
+		assert.Equalf(t, expected, fields[name].Type.String(), "type of %s", name)
+	}
+}
@@ -7,4 +7,17 @@ const (
 	FieldTypeInt     FieldType = "Int"
 	FieldTypeFloat   FieldType = "Float"
 	FieldTypeBoolean FieldType = "Boolean"
+	FieldTypeID      FieldType = "ID"
+	// FieldTypeDateTime RFC 3339, e.g. "2024-01-02T15:04:05Z".
+	FieldTypeDateTime FieldType = "DateTime"
+	// FieldTypeDate e.g. "2024-01-02".
+	FieldTypeDate FieldType = "Date"
+	// FieldTypeTime a time of day, e.g. "15:04:05".
+	FieldTypeTime FieldType = "Time"
+	// FieldTypeJSON any JSON value, e.g. the content of a JSON column.
+	FieldTypeJSON FieldType = "JSON"
+	// FieldTypeBigInt a 64-bit integer serialized as a string.
+	FieldTypeBigInt FieldType = "BigInt"
+	// FieldTypeDecimal an exact decimal number serialized as a string, e.g. "12.30".
+	FieldTypeDecimal FieldType = "Decimal"
 )
@@ -0,0 +1,238 @@
+package core
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+
+	"github.com/graphql-go/graphql"
+
+	"github.com/Finovate/go-gql-builder/pkg/core/dataloader"
+)
+
+const (
+	// NodeInterfaceName is the name of the Relay Node interface, see NodeRegistry.EnableNodeQuery.
+	NodeInterfaceName = "Node"
+	// GlobalIDField is the field of the global ID on the objects implementing the Node interface.
+	GlobalIDField = "id"
+	// RawIDField keeps the id field declared by the Node when it is replaced by the global ID,
+	// e.g. the primary key expected by the arguments user(id:) and filter: {id: ...}.
+	RawIDField = "rawId"
+)
+
+// NodeFetcher is implemented by a Node whose objects can be refetched by a global ID, see NodeRegistry.EnableNodeQuery.
+type NodeFetcher interface {
+	// Identifiable reports whether the objects of the Node have a key, e.g. the table has primary keys.
+	Identifiable() bool
+	// NodeKey returns the key of an object resolved by the Node, e.g. its primary key values.
+	NodeKey(value interface{}) ([]interface{}, error)
+	// FetchNodes returns the objects of the keys in the order of keys, nil for the ones which do not exist.
+	// The fields to resolve are the ones selected by p.
+	FetchNodes(p graphql.ResolveParams, keys [][]interface{}) ([]interface{}, error)
+}
+
+// EncodeGlobalID returns the opaque global ID of an object of the Node type t, made of the type and the key values.
+func EncodeGlobalID(t FieldType, key []interface{}) (string, error) {
+	payload, err := json.Marshal(append([]interface{}{t}, key...))
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(payload), nil
+}
+
+// DecodeGlobalID returns the Node type and the key values of a global ID, the numbers of the key are returned as strings.
+func DecodeGlobalID(id string) (FieldType, []interface{}, error) {
+	payload, err := base64.RawURLEncoding.DecodeString(id)
+	if err != nil {
+		return "", nil, fmt.Errorf("invalid node id: %s", id)
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(payload))
+	decoder.UseNumber()
+	var values []interface{}
+	if err := decoder.Decode(&values); err != nil || len(values) < 2 {
+		return "", nil, fmt.Errorf("invalid node id: %s", id)
+	}
+	t, ok := values[0].(string)
+	if !ok {
+		return "", nil, fmt.Errorf("invalid node id: %s", id)
+	}
+
+	key := values[1:]
+	for i, value := range key {
+		if number, ok := value.(json.Number); ok {
+			key[i] = number.String()
+		}
+	}
+	return FieldType(t), key, nil
+}
+
+// EnableNodeQuery adds the Relay global object identification to the schema:
+// the Node interface { id: ID! }, implemented by the objects of every identifiable NodeFetcher,
+// and the root fields node(id: ID!): Node and nodes(ids: [ID!]!): [Node]!.
+// The id field of the implementing objects returns the global ID, it replaces the id field declared by the Node,
+// which is still available as rawId, see RawIDField.
+func (h *NodeRegistry) EnableNodeQuery() {
+	h.nodeQuery = true
+}
+
+// NodeQueryEnabled reports whether EnableNodeQuery is called.
+func (h *NodeRegistry) NodeQueryEnabled() bool {
+	return h.nodeQuery
+}
+
+// nodeFetcher returns the NodeFetcher of the Node type when it takes part in the Node interface.
+func (h *NodeRegistry) nodeFetcher(flag FieldType) (NodeFetcher, bool) {
+	if !h.nodeQuery {
+		return nil, false
+	}
+	node, ok := h.nodesByType[flag]
+	if !ok {
+		return nil, false
+	}
+	fetcher, ok := node.(NodeFetcher)
+	if !ok || !fetcher.Identifiable() {
+		return nil, false
+	}
+	return fetcher, true
+}
+
+// preLoadNodeInterface 创建 Node interface, 需要在 Node 的 object 创建之后调用.
+func (h *NodeRegistry) preLoadNodeInterface() {
+	if !h.nodeQuery {
+		return
+	}
+	h.preCache[NodeInterfaceName] = graphql.NewInterface(graphql.InterfaceConfig{
+		Name:        NodeInterfaceName,
+		Description: "An object with a global ID",
+		Fields: graphql.Fields{
+			GlobalIDField: &graphql.Field{Type: graphql.NewNonNull(graphql.ID)},
+		},
+		ResolveType: h.resolveType(nil),
+	})
+}
+
+// globalIDField returns the id field of the objects of a Node taking part in the Node interface.
+func (h *NodeRegistry) globalIDField(flag FieldType, fetcher NodeFetcher) *graphql.Field {
+	return &graphql.Field{
+		Type:        graphql.NewNonNull(graphql.ID),
+		Description: "the global ID of the object",
+		Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+			key, err := fetcher.NodeKey(p.Source)
+			if err != nil {
+				return nil, err
+			}
+			return EncodeGlobalID(flag, key)
+		},
+	}
+}
+
+// rawIDField returns the id field declared by a Node under RawIDField, it still resolves the id of the source.
+func rawIDField(field *graphql.Field) *graphql.Field {
+	resolve := field.Resolve
+	if resolve == nil {
+		resolve = graphql.DefaultResolveFn
+	}
+	raw := *field
+	raw.Name = RawIDField
+	raw.Resolve = func(p graphql.ResolveParams) (interface{}, error) {
+		p.Info.FieldName = GlobalIDField
+		return resolve(p)
+	}
+	return &raw
+}
+
+// buildNodeQueries 生成 node 和 nodes 两个 root field.
+func (h *NodeRegistry) buildNodeQueries() graphql.Fields {
+	nodeInterface, ok := h.preCache[NodeInterfaceName].(*graphql.Interface)
+	if !h.nodeQuery || !ok {
+		return nil
+	}
+
+	return graphql.Fields{
+		"node": &graphql.Field{
+			Type:        nodeInterface,
+			Description: "Fetches an object given its global ID",
+			Args: graphql.FieldConfigArgument{
+				"id": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.ID)},
+			},
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				id, _ := p.Args["id"].(string)
+				thunk, err := h.loadNode(p, id)
+				if err != nil {
+					return nil, err
+				}
+				return thunk, nil
+			},
+		},
+		"nodes": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.NewList(nodeInterface)),
+			Description: "Fetches objects given their global IDs, null for the ones which do not exist",
+			Args: graphql.FieldConfigArgument{
+				"ids": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(graphql.ID)))},
+			},
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				ids, _ := p.Args["ids"].([]interface{})
+				thunks := make([]func() (interface{}, error), len(ids))
+				for i, id := range ids {
+					thunk, err := h.loadNode(p, fmt.Sprint(id))
+					if err != nil {
+						return nil, err
+					}
+					thunks[i] = thunk
+				}
+				return func() (interface{}, error) {
+					values := make([]interface{}, len(thunks))
+					for i, thunk := range thunks {
+						value, err := thunk()
+						if err != nil {
+							return nil, err
+						}
+						values[i] = value
+					}
+					return values, nil
+				}, nil
+			},
+		},
+	}
+}
+
+// loadNode dispatches the global ID to the NodeFetcher of its type, the IDs of the same type are fetched together.
+func (h *NodeRegistry) loadNode(p graphql.ResolveParams, id string) (func() (interface{}, error), error) {
+	flag, _, err := DecodeGlobalID(id)
+	if err != nil {
+		return nil, err
+	}
+	fetcher, ok := h.nodeFetcher(flag)
+	if !ok {
+		return nil, fmt.Errorf("invalid node id: %s", id)
+	}
+
+	name := fmt.Sprintf("node.%s", flag)
+	if len(p.Info.FieldASTs) > 0 {
+		name = fmt.Sprintf("%s@%p", name, p.Info.FieldASTs[0])
+	}
+	return dataloader.Load(p.Context, name, id, func(ctx context.Context, ids []interface{}) ([]interface{}, error) {
+		keys := make([][]interface{}, len(ids))
+		for i, id := range ids {
+			_, key, err := DecodeGlobalID(id.(string))
+			if err != nil {
+				return nil, err
+			}
+			keys[i] = key
+		}
+
+		values, err := fetcher.FetchNodes(p, keys)
+		if err != nil {
+			return nil, err
+		}
+		for _, value := range values {
+			if row, ok := value.(map[string]interface{}); ok {
+				row[TypenameKey] = flag
+			}
+		}
+		return values, nil
+	}), nil
+}
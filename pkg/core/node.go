@@ -30,6 +30,32 @@ type Node interface {
 	SetRegistry(*NodeRegistry)
 }
 
+// ArgumentTypeBuilder is optionally implemented by a Node whose Arguments have an input type
+// derived from the Node itself, e.g. UserFilter { id: StringFilter, name: StringFilter }.
+// When it returns nil, the input type of the Argument (Argument.GetArgumentType) is used.
+type ArgumentTypeBuilder interface {
+	BuildArgumentType(arg argument.Argument) graphql.Input
+}
+
+// ArgumentSetBuilder is optionally implemented by a Node picking its arguments from the arguments of the registry,
+// e.g. by embedding argument.DefaultArgumentBuilder, see NodeRegistry.Arguments.
+// The arguments of the other Nodes are the ones returned by BuildArgs.
+type ArgumentSetBuilder interface {
+	ArgumentSet(defaults *argument.Set) *argument.Set
+}
+
+// MutationBuilder is optionally implemented by a Node contributing fields to the Mutation root,
+// the fields are only built when mutations are enabled, see NodeRegistry.EnableMutation.
+type MutationBuilder interface {
+	BuildMutations() (graphql.Fields, error)
+}
+
+// QueryBuilder is optionally implemented by a Node contributing extra fields to the Query root,
+// besides the field of the Node itself, e.g. usersConnection.
+type QueryBuilder interface {
+	BuildQueries() (graphql.Fields, error)
+}
+
 type BaseNode struct {
 	registry *NodeRegistry
 }
@@ -1,12 +1,18 @@
 package core
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 	"net/http"
+	"time"
 
 	"github.com/graphql-go/graphql"
 	"github.com/graphql-go/handler"
+
+	"github.com/Finovate/go-gql-builder/pkg/core/argument"
+	"github.com/Finovate/go-gql-builder/pkg/core/dataloader"
+	"github.com/Finovate/go-gql-builder/pkg/dialect"
 )
 
 // NodeRegistry is a key component in the go-gql-builder framework,
@@ -17,27 +23,59 @@ type NodeRegistry struct {
 
 	fieldsMap map[FieldType]graphql.Fields
 	argsMap   map[FieldType]graphql.FieldConfigArgument
+	// arguments 所有 Node 默认可用的参数, argumentSets 每个 Node 选取的参数, 见 Arguments.
+	arguments    *argument.Set
+	argumentSets map[FieldType]*argument.Set
+	// fieldArguments Field.AddArgument 声明的参数名, key 为 object 名与 field 名, 见 IsFieldArgument.
+	fieldArguments map[string]map[string]struct{}
 
-	// 用一个缓存先初始化所有的node, 以免在具体构建field时依赖了一个不存在的node.
+	// 用一个缓存先初始化所有的node(graphql.Object), 以免在具体构建field时依赖了一个不存在的node.
 	// 比如user.department 依赖了 department 这个node，在处理这个field的时候如果没有预创建这一步，
 	// 那么就会导致找不到对应的类型，从而导致field创建失败.
 	// 在最终处理node的时候，也应该从缓存中加载相应的指针出来进行最终的构建.
 	preCache map[FieldType]graphql.Output
 
 	completeCache graphql.Fields
-
-	// TODO  HubSet 框架支持多个数据源
-	db *sql.DB
+	// building 记录正在构建的node, 用于处理node之间的循环引用.
+	building map[FieldType]struct{}
+
+	// mutationEnabled 为 true 时, 生成 Mutation root, 见 EnableMutation.
+	mutationEnabled bool
+	// mutations 由 RegisterMutation 注册的 mutation field, 不受 mutationEnabled 影响.
+	mutations graphql.Fields
+	// transactional 为 true 时, 同一个 operation 的 mutation field 在同一个事务中执行, 见 EnableMutationTransaction.
+	transactional bool
+
+	// dataSources 按名称注册的数据源, SetDB 设置的是 DefaultDataSource.
+	dataSources map[string]*DataSource
+	// dialect 数据库的 SQL 方言, 为 nil 时使用 dialect.Default.
+	dialect dialect.Dialect
+	// queryTimeout 每条 SQL 的超时时间, 为 0 时不限制.
+	queryTimeout time.Duration
+	// scalars 注册到当前 registry 的自定义类型和枚举, 优先于 defaultFieldTypeMapping.
+	scalars map[FieldType]graphql.Output
+	// interfaces 和 unions 的类型在 Node 的 object 之后创建, 见 preLoadAbstracts.
+	interfaces []*Interface
+	unions     []*Union
+	// nodeQuery 生成 Relay 的 Node interface 以及 node/nodes 查询, 见 EnableNodeQuery.
+	nodeQuery bool
 }
 
 func NewRegistry() *NodeRegistry {
 	return &NodeRegistry{
-		nodes:         make([]Node, 0),
-		nodesByType:   make(map[FieldType]Node),
-		fieldsMap:     make(map[FieldType]graphql.Fields),
-		argsMap:       make(map[FieldType]graphql.FieldConfigArgument),
-		preCache:      make(map[FieldType]graphql.Output),
-		completeCache: make(graphql.Fields),
+		nodes:          make([]Node, 0),
+		nodesByType:    make(map[FieldType]Node),
+		fieldsMap:      make(map[FieldType]graphql.Fields),
+		argsMap:        make(map[FieldType]graphql.FieldConfigArgument),
+		arguments:      argument.DefaultSet.Clone(),
+		argumentSets:   make(map[FieldType]*argument.Set),
+		fieldArguments: make(map[string]map[string]struct{}),
+		preCache:       make(map[FieldType]graphql.Output),
+		completeCache:  make(graphql.Fields),
+		building:       make(map[FieldType]struct{}),
+		mutations:      make(graphql.Fields),
+		dataSources:    make(map[string]*DataSource),
+		scalars:        make(map[FieldType]graphql.Output),
 	}
 }
 
@@ -48,21 +86,109 @@ func DefaultRegistry() *NodeRegistry {
 	return registry
 }
 
+// GetDB returns the database of DefaultDataSource.
 func (h *NodeRegistry) GetDB() *sql.DB {
-	return h.db
+	source, err := h.GetDataSource(DefaultDataSource)
+	if err != nil {
+		return nil
+	}
+	return source.DB()
 }
 
+// SetDB sets the database of DefaultDataSource.
 func (h *NodeRegistry) SetDB(db *sql.DB) {
-	h.db = db
+	h.RegisterDataSource(DefaultDataSource, db)
+}
+
+// GetDialect returns the SQL dialect of the registry, adapters without their own dialect use it.
+func (h *NodeRegistry) GetDialect() dialect.Dialect {
+	if h.dialect == nil {
+		return dialect.Default
+	}
+	return h.dialect
+}
+
+// SetDialect selects the SQL dialect of the registry DB, e.g. dialect.PostgreSQL.
+func (h *NodeRegistry) SetDialect(d dialect.Dialect) {
+	h.dialect = d
 }
 
+// QueryTimeout returns the timeout of every statement, 0 means no timeout.
+func (h *NodeRegistry) QueryTimeout() time.Duration {
+	return h.queryTimeout
+}
+
+// SetQueryTimeout limits the time of every statement run by the resolvers, Nodes may set their own timeout.
+// A statement exceeding it fails with an Error of ErrorCodeTimeout.
+func (h *NodeRegistry) SetQueryTimeout(timeout time.Duration) {
+	h.queryTimeout = timeout
+}
+
+// EnableMutation makes the registry build a Mutation root from the Nodes implementing MutationBuilder.
+func (h *NodeRegistry) EnableMutation() {
+	h.mutationEnabled = true
+}
+
+// EnableMutationTransaction makes all mutation fields of one operation run in a single transaction.
+// The transaction is begun on the data source used by the operation and committed when every field succeeds,
+// the first failing field rolls it back, the following fields are skipped and the succeeded ones are reported as rolled back.
+// Resolvers take part in the transaction by running their statements with Querier(p.Context, dataSource).
+// A transaction can not span several data sources, since their commits would not be atomic together,
+// the mutation field using a second data source fails and the transaction is rolled back.
+func (h *NodeRegistry) EnableMutationTransaction() {
+	h.transactional = true
+}
+
+// RegisterMutation adds a mutation field resolved by Go code, it is part of the Mutation root
+// whether the generated mutations are enabled or not.
+func (h *NodeRegistry) RegisterMutation(name string, field *graphql.Field) {
+	h.mutations[name] = field
+}
+
+// Querier returns the Querier writing to the data source, it is the transaction of the mutation being executed
+// in the context, or the primary database when there is none. An empty name means DefaultDataSource.
+func (h *NodeRegistry) Querier(ctx context.Context, dataSource string) (Querier, error) {
+	source, err := h.GetDataSource(dataSource)
+	if err != nil {
+		return nil, err
+	}
+	if t := transactionFromContext(ctx); t != nil && t.registry == h {
+		return t.querier(ctx, source)
+	}
+	return source.DB(), nil
+}
+
+// ReadQuerier returns the Querier reading from the data source, the queries of a mutation operation
+// are sent to the same Querier as its writes, the other ones to a replica unless WithReadPrimary is set.
+func (h *NodeRegistry) ReadQuerier(ctx context.Context, dataSource string) (Querier, error) {
+	source, err := h.GetDataSource(dataSource)
+	if err != nil {
+		return nil, err
+	}
+	if t := transactionFromContext(ctx); t != nil && t.registry == h && t.mutating() {
+		return t.querier(ctx, source)
+	}
+	return source.replica(ctx), nil
+}
+
+// Register adds a Node to the registry, with the enums it declares, see EnumBuilder.
+// An enum whose type is already registered is left as it is.
 func (h *NodeRegistry) Register(delegate Node) {
 	h.nodes = append(h.nodes, delegate)
 	h.nodesByType[delegate.Type()] = delegate
 	delegate.SetRegistry(h)
+
+	if builder, ok := delegate.(EnumBuilder); ok {
+		for _, enum := range builder.BuildEnums() {
+			if _, ok := h.scalars[enum.Type]; !ok {
+				h.RegisterEnum(enum)
+			}
+		}
+	}
 }
 
-func (h *NodeRegistry) getNode(typeName FieldType) (Node, error) {
+// GetNode returns the registered Node of the type.
+func (h *NodeRegistry) GetNode(typeName FieldType) (Node, error) {
 	node, ok := h.nodesByType[typeName]
 	if !ok {
 		return nil, fmt.Errorf("unsupported node type: %s", typeName)
@@ -70,21 +196,83 @@ func (h *NodeRegistry) getNode(typeName FieldType) (Node, error) {
 	return node, nil
 }
 
+// GetObject returns the graphql.Object of the Node type, it is available once the schema building has started.
+func (h *NodeRegistry) GetObject(typeName FieldType) (*graphql.Object, error) {
+	obj, ok := h.preCache[typeName].(*graphql.Object)
+	if !ok {
+		return nil, fmt.Errorf("unsupported node type: %s", typeName)
+	}
+	return obj, nil
+}
+
+// GetOutput returns the type of the values of a Node, the interface or union of a PolymorphicNode,
+// or the object of any other Node. It is only available once the Node is built.
+func (h *NodeRegistry) GetOutput(typeName FieldType) (graphql.Output, error) {
+	node, err := h.GetNode(typeName)
+	if err != nil {
+		return nil, err
+	}
+	outputType, err := h.outputType(node)
+	if err != nil {
+		return nil, err
+	}
+	output, ok := h.preCache[outputType]
+	if !ok {
+		return nil, fmt.Errorf("unsupported field type: %s", outputType)
+	}
+	return output, nil
+}
+
+// Arguments returns the arguments available to the Nodes of this registry, a copy of argument.DefaultSet,
+// so the arguments registered here, or removed from here, do not affect other registries. e.g.
+//
+//	registry.Arguments().Remove(sqlArgument.LimitArgumentType)
+//
+// A Node implementing ArgumentSetBuilder picks its own arguments from them.
+// It should be called before the schema is built.
+func (h *NodeRegistry) Arguments() *argument.Set {
+	return h.arguments
+}
+
+// Argument creates an instance of the argument of the Node type by typename, nil when the Node has no such argument.
+func (h *NodeRegistry) Argument(typeName FieldType, typename string) argument.Argument {
+	if set, ok := h.argumentSets[typeName]; ok {
+		return set.Factory(typename)
+	}
+	return h.arguments.Factory(typename)
+}
+
+// GetArgs returns the arguments of the Node type, they are available once the Node is built.
+func (h *NodeRegistry) GetArgs(typeName FieldType) graphql.FieldConfigArgument {
+	return h.argsMap[typeName]
+}
+
 func (h *NodeRegistry) BuildHandler() (http.Handler, error) {
 	schema, err := h.buildSchema()
 	if err != nil {
 		return nil, err
 	}
 
-	return handler.New(&handler.Config{
+	graphqlHandler := handler.New(&handler.Config{
 		Schema: schema,
 		Pretty: true,
+	})
+	// 每个请求使用独立的 dataloader, 同一层级的 resolver 可以合并查询.
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		graphqlHandler.ContextHandler(dataloader.NewContext(r.Context()), w, r)
 	}), nil
 }
 
 func (h *NodeRegistry) buildSchema() (*graphql.Schema, error) {
+	if err := h.validateDataSources(); err != nil {
+		return nil, err
+	}
 
 	h.preLoadDelegate()
+	if err := h.preLoadAbstracts(); err != nil {
+		return nil, err
+	}
+	h.preLoadNodeInterface()
 
 	for _, delegate := range h.nodes {
 		err := h.buildNode(delegate)
@@ -92,6 +280,13 @@ func (h *NodeRegistry) buildSchema() (*graphql.Schema, error) {
 			return nil, err
 		}
 	}
+	if err := h.buildAbstracts(); err != nil {
+		return nil, err
+	}
+
+	if err := h.buildQueries(); err != nil {
+		return nil, err
+	}
 
 	// 生成schema(逻辑不变)
 	queryType := graphql.NewObject(
@@ -101,11 +296,23 @@ func (h *NodeRegistry) buildSchema() (*graphql.Schema, error) {
 		},
 	)
 
-	schema, err := graphql.NewSchema(
-		graphql.SchemaConfig{
-			Query: queryType,
-		},
-	)
+	mutationType, err := h.buildMutation()
+	if err != nil {
+		return nil, err
+	}
+
+	schemaConfig := graphql.SchemaConfig{
+		Query:    queryType,
+		Mutation: mutationType,
+	}
+	if mutationType != nil {
+		schemaConfig.Extensions = append(schemaConfig.Extensions, &mutationExtension{
+			registry:      h,
+			transactional: h.transactional,
+		})
+	}
+
+	schema, err := graphql.NewSchema(schemaConfig)
 	if err != nil {
 		return nil, err
 	}
@@ -113,46 +320,151 @@ func (h *NodeRegistry) buildSchema() (*graphql.Schema, error) {
 	return &schema, nil
 }
 
+// buildQueries 将所有 QueryBuilder 生成的 field 加入 Query root.
+func (h *NodeRegistry) buildQueries() error {
+	for _, delegate := range h.nodes {
+		builder, ok := delegate.(QueryBuilder)
+		if !ok {
+			continue
+		}
+		queries, err := builder.BuildQueries()
+		if err != nil {
+			return err
+		}
+		if err := h.addQueries(queries); err != nil {
+			return err
+		}
+	}
+	return h.addQueries(h.buildNodeQueries())
+}
+
+func (h *NodeRegistry) addQueries(queries graphql.Fields) error {
+	for name, field := range queries {
+		if _, ok := h.completeCache[name]; ok {
+			return fmt.Errorf("duplicate query field: %s", name)
+		}
+		h.completeCache[name] = field
+	}
+	return nil
+}
+
+// buildMutation 汇总 RegisterMutation 注册的以及所有 MutationBuilder 生成的 field, 没有任何 field 时返回 nil.
+func (h *NodeRegistry) buildMutation() (*graphql.Object, error) {
+	fields := make(graphql.Fields)
+	for name, field := range h.mutations {
+		fields[name] = field
+	}
+	for _, delegate := range h.nodes {
+		builder, ok := delegate.(MutationBuilder)
+		if !ok || !h.mutationEnabled {
+			// 未开启 mutation 时只包含 RegisterMutation 注册的 field.
+			continue
+		}
+		mutations, err := builder.BuildMutations()
+		if err != nil {
+			return nil, err
+		}
+		for name, field := range mutations {
+			if _, ok := fields[name]; ok {
+				return nil, fmt.Errorf("duplicate mutation field: %s", name)
+			}
+			fields[name] = field
+		}
+	}
+
+	if len(fields) == 0 {
+		return nil, nil
+	}
+	for name, field := range fields {
+		wrapped := *field
+		wrapped.Resolve = wrapMutation(field.Resolve)
+		fields[name] = &wrapped
+	}
+	return graphql.NewObject(graphql.ObjectConfig{
+		Name:   "Mutation",
+		Fields: fields,
+	}), nil
+}
+
 func (h *NodeRegistry) preLoadDelegate() {
 	// 预加载delegate
 	for _, delegate := range h.nodes {
+		flag := delegate.Type()
 		obj := graphql.NewObject(graphql.ObjectConfig{
 			Name:   delegate.Name(),
 			Fields: make(graphql.Fields),
+			Interfaces: graphql.InterfacesThunk(func() []*graphql.Interface {
+				return h.nodeInterfaces(flag)
+			}),
 		})
 
-		var result graphql.Output
-		result = obj
-		if delegate.IsList() {
-			result = graphql.NewList(obj)
-		}
-
-		h.preCache[delegate.Type()] = result
+		h.preCache[delegate.Type()] = obj
 	}
 }
 
 // initNodeField Node由一组Field组成，这个方法中会解析Node下面的Field，并将其转换为graphql.Field以及Argument
 func (h *NodeRegistry) initNodeField(delegate Node) error {
+	// args 先于 fields 生成, 当 fields 循环引用当前 node 时(如 department.users), 可以直接使用当前 node 的 args.
+	args := make(graphql.FieldConfigArgument)
+	var argList []argument.Argument
+	if setBuilder, ok := delegate.(ArgumentSetBuilder); ok {
+		set := setBuilder.ArgumentSet(h.arguments)
+		h.argumentSets[delegate.Type()] = set
+		argList = set.Build()
+	} else {
+		argList = delegate.BuildArgs()
+		h.argumentSets[delegate.Type()] = buildArgsSet(delegate, argList)
+	}
+	typeBuilder, _ := delegate.(ArgumentTypeBuilder)
+	for _, arg := range argList {
+		argType := arg.GetArgumentType()
+		if typeBuilder != nil {
+			if nodeArgType := typeBuilder.BuildArgumentType(arg); nodeArgType != nil {
+				argType = nodeArgType
+			}
+		}
+		args[arg.TypeName()] = &graphql.ArgumentConfig{Type: argType}
+	}
+	h.argsMap[delegate.Type()] = args
+
 	rawFields := delegate.BuildFields()
 	fields := make(graphql.Fields)
-	args := make(graphql.FieldConfigArgument)
 	for _, f := range rawFields {
 		convert, err := f.Convert(h)
 		if err != nil {
 			return err
 		}
 		fields[f.fieldName] = convert
-	}
-	argList := delegate.BuildArgs()
-	for _, arg := range argList {
-		args[arg.TypeName()] = &graphql.ArgumentConfig{Type: arg.GetArgumentType()}
+		for _, arg := range f.arguments {
+			key := delegate.Name() + "." + f.fieldName
+			if h.fieldArguments[key] == nil {
+				h.fieldArguments[key] = make(map[string]struct{})
+			}
+			h.fieldArguments[key][arg.name] = struct{}{}
+		}
 	}
 
 	h.fieldsMap[delegate.Type()] = fields
-	h.argsMap[delegate.Type()] = args
 	return nil
 }
 
+// buildArgsSet 记录 BuildArgs 返回的参数, 解析时由 Argument 创建同样的参数, 每次调用 BuildArgs 得到新的实例.
+func buildArgsSet(delegate Node, argList []argument.Argument) *argument.Set {
+	set := argument.NewSet()
+	for _, arg := range argList {
+		typename := arg.TypeName()
+		set.Register(typename, func() argument.Argument {
+			for _, arg := range delegate.BuildArgs() {
+				if arg.TypeName() == typename {
+					return arg
+				}
+			}
+			return nil
+		})
+	}
+	return set
+}
+
 // buildNode 入参是一个Node，该方法将会根据Node的信息对应的schema(graphql.Field)同时存进completeCache中
 // 如:
 //
@@ -171,22 +483,30 @@ func (h *NodeRegistry) buildNode(delegate Node) error {
 	if _, ok := h.completeCache[delegate.Name()]; ok {
 		return nil
 	}
+	// 循环引用(user.department -> department.users -> user)时直接返回, 对象的 fields 由外层的调用补充完整.
+	if _, ok := h.building[delegate.Type()]; ok {
+		return nil
+	}
+	h.building[delegate.Type()] = struct{}{}
+	defer delete(h.building, delegate.Type())
+
 	err := h.initNodeField(delegate)
 	if err != nil {
 		return err
 	}
 
-	var obj *graphql.Object
-
-	cache := h.preCache[delegate.Type()]
-	if tmp, ok := cache.(*graphql.List); ok {
-		obj = tmp.OfType.(*graphql.Object)
-	} else if tmp, ok := cache.(*graphql.Object); ok {
-		obj = tmp
+	obj, ok := h.preCache[delegate.Type()].(*graphql.Object)
+	if !ok {
+		return fmt.Errorf("unsupported field type: %s", delegate.Type())
 	}
 
-	if obj == nil {
-		return fmt.Errorf("unsupported field type: %s", delegate.Type())
+	outputType, err := h.outputType(delegate)
+	if err != nil {
+		return err
+	}
+	output := h.preCache[outputType]
+	if delegate.IsList() {
+		output = graphql.NewList(output)
 	}
 
 	fields := h.fieldsMap[delegate.Type()]
@@ -194,17 +514,41 @@ func (h *NodeRegistry) buildNode(delegate Node) error {
 	for name, field := range fields {
 		obj.AddFieldConfig(name, field)
 	}
+	if fetcher, ok := h.nodeFetcher(delegate.Type()); ok {
+		if _, declared := fields[RawIDField]; !declared && fields[GlobalIDField] != nil {
+			obj.AddFieldConfig(RawIDField, rawIDField(fields[GlobalIDField]))
+		}
+		obj.AddFieldConfig(GlobalIDField, h.globalIDField(delegate.Type(), fetcher))
+	}
 
 	h.completeCache[delegate.Name()] = &graphql.Field{
-		Type:    cache,
+		Type:    output,
 		Args:    args,
 		Resolve: delegate.Resolve(),
 	}
 	return nil
 }
 
+// RegisterScalar makes a custom type usable as the FieldType of the fields and the ColumnType of the columns
+// of this registry only, e.g. RegisterScalar("Money", moneyScalar). A built-in FieldType can be replaced as well.
+// t should be a *graphql.Scalar or a *graphql.Enum, so it can also be used by the filter and mutation inputs.
+// It should be called before the schema is built.
+func (h *NodeRegistry) RegisterScalar(flag FieldType, t graphql.Output) {
+	h.scalars[flag] = t
+}
+
+// ScalarType returns the type of a scalar FieldType, the ones registered with RegisterScalar
+// or the built-in ones, e.g. FieldTypeDateTime.
+func (h *NodeRegistry) ScalarType(flag FieldType) (graphql.Output, bool) {
+	if fieldType, ok := h.scalars[flag]; ok {
+		return fieldType, true
+	}
+	fieldType, ok := defaultFieldTypeMapping[flag]
+	return fieldType, ok
+}
+
 func (h *NodeRegistry) loadFieldType(flag FieldType) (out graphql.Output, isDefaultFieldType bool, err error) {
-	if fieldType, ok := defaultFieldTypeMapping[flag]; ok {
+	if fieldType, ok := h.ScalarType(flag); ok {
 		return fieldType, true, nil
 	}
 
@@ -219,8 +563,15 @@ func (h *NodeRegistry) loadFieldType(flag FieldType) (out graphql.Output, isDefa
 var registry *NodeRegistry
 
 var defaultFieldTypeMapping = map[FieldType]graphql.Output{
-	FieldTypeString:  graphql.String,
-	FieldTypeInt:     graphql.Int,
-	FieldTypeFloat:   graphql.Float,
-	FieldTypeBoolean: graphql.Boolean,
+	FieldTypeString:   graphql.String,
+	FieldTypeInt:      graphql.Int,
+	FieldTypeFloat:    graphql.Float,
+	FieldTypeBoolean:  graphql.Boolean,
+	FieldTypeID:       graphql.ID,
+	FieldTypeDateTime: DateTime,
+	FieldTypeDate:     Date,
+	FieldTypeTime:     Time,
+	FieldTypeJSON:     JSON,
+	FieldTypeBigInt:   BigInt,
+	FieldTypeDecimal:  Decimal,
 }
@@ -0,0 +1,79 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/graphql-go/graphql"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Finovate/go-gql-builder/pkg/core/argument"
+)
+
+type stubArgument struct {
+	typename string
+}
+
+func (a *stubArgument) TypeName() string               { return a.typename }
+func (a *stubArgument) Validate(interface{}) error     { return nil }
+func (a *stubArgument) GetArgumentType() graphql.Input { return graphql.String }
+
+func stubBuilder(typename string) argument.Builder {
+	return func() argument.Argument { return &stubArgument{typename: typename} }
+}
+
+// argsNode is a Node whose arguments are returned by BuildArgs.
+type argsNode struct {
+	thingNode
+	args []string
+}
+
+func (n *argsNode) BuildArgs() []argument.Argument {
+	args := make([]argument.Argument, len(n.args))
+	for i, typename := range n.args {
+		args[i] = &stubArgument{typename: typename}
+	}
+	return args
+}
+
+// setNode is a Node picking its arguments from the arguments of the registry.
+type setNode struct {
+	thingNode
+	argument.DefaultArgumentBuilder
+}
+
+func (n *setNode) Name() string                   { return "items" }
+func (n *setNode) Type() FieldType                { return "item" }
+func (n *setNode) BuildArgs() []argument.Argument { return n.DefaultArgumentBuilder.BuildArgs() }
+
+func TestRegistry_Argument(t *testing.T) {
+	registry := NewRegistry()
+	registry.Arguments().Register("search", stubBuilder("search"))
+	registry.Register(&argsNode{args: []string{"custom"}})
+	items := &setNode{}
+	items.RegisterArgument("extra", stubBuilder("extra"))
+	registry.Register(items)
+	_, err := registry.BuildHandler()
+	assert.NoError(t, err)
+
+	// the arguments of BuildArgs are the only ones of the Node.
+	assert.Equal(t, "custom", registry.Argument("thing", "custom").TypeName())
+	assert.NotSame(t, registry.Argument("thing", "custom"), registry.Argument("thing", "custom"))
+	assert.Nil(t, registry.Argument("thing", "search"))
+	assert.Contains(t, registry.GetArgs("thing"), "custom")
+
+	// the Node picks the arguments of the registry.
+	assert.Equal(t, "search", registry.Argument("item", "search").TypeName())
+	assert.Equal(t, "extra", registry.Argument("item", "extra").TypeName())
+	assert.Nil(t, registry.Argument("item", "custom"))
+	assert.Contains(t, registry.GetArgs("item"), "search")
+}
+
+func TestRegistry_ArgumentsAreCloned(t *testing.T) {
+	a, b := NewRegistry(), NewRegistry()
+	a.Arguments().Register("search", stubBuilder("search"))
+
+	assert.NotNil(t, a.Arguments().Factory("search"))
+	assert.Nil(t, b.Arguments().Factory("search"))
+	assert.Nil(t, argument.DefaultSet.Factory("search"))
+	assert.Equal(t, argument.DefaultSet.TypeNames(), b.Arguments().TypeNames())
+}
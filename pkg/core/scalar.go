@@ -0,0 +1,282 @@
+package core
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/language/ast"
+)
+
+// The layouts of the Date and Time scalars, DateTime uses time.RFC3339Nano.
+const (
+	DateLayout = "2006-01-02"
+	TimeLayout = "15:04:05.999999999"
+)
+
+// The values parsed by the scalars can be bound to SQL statements as they are:
+// DateTime and Date -> time.Time, Time -> string, JSON -> json.RawMessage, BigInt -> int64, Decimal -> string.
+var (
+	// DateTime is serialized as an RFC 3339 string, e.g. "2024-01-02T15:04:05Z".
+	DateTime = graphql.NewScalar(graphql.ScalarConfig{
+		Name:        "DateTime",
+		Description: "The `DateTime` scalar type represents a point in time, serialized as an RFC 3339 string",
+		Serialize: func(value interface{}) interface{} {
+			return serializeTime(value, time.RFC3339Nano, time.RFC3339Nano, "2006-01-02 15:04:05.999999999")
+		},
+		ParseValue:   func(value interface{}) interface{} { return parseTime(value, time.RFC3339Nano) },
+		ParseLiteral: parseStringLiteral(func(value string) interface{} { return parseTime(value, time.RFC3339Nano) }),
+	})
+
+	// Date is serialized as "2006-01-02".
+	Date = graphql.NewScalar(graphql.ScalarConfig{
+		Name:        "Date",
+		Description: "The `Date` scalar type represents a calendar date, serialized as YYYY-MM-DD",
+		Serialize: func(value interface{}) interface{} {
+			return serializeTime(value, DateLayout, DateLayout, time.RFC3339Nano, "2006-01-02 15:04:05.999999999")
+		},
+		ParseValue:   func(value interface{}) interface{} { return parseTime(value, DateLayout) },
+		ParseLiteral: parseStringLiteral(func(value string) interface{} { return parseTime(value, DateLayout) }),
+	})
+
+	// Time is a time of day serialized as "15:04:05", the fraction of seconds is only present when it is not zero.
+	Time = graphql.NewScalar(graphql.ScalarConfig{
+		Name:        "Time",
+		Description: "The `Time` scalar type represents a time of day, serialized as HH:MM:SS[.fraction]",
+		Serialize: func(value interface{}) interface{} {
+			return serializeTime(value, TimeLayout, TimeLayout, time.RFC3339Nano)
+		},
+		ParseValue: func(value interface{}) interface{} {
+			if t, ok := parseTime(value, TimeLayout).(time.Time); ok {
+				return t.Format(TimeLayout)
+			}
+			return nil
+		},
+		ParseLiteral: parseStringLiteral(func(value string) interface{} {
+			if t, ok := parseTime(value, TimeLayout).(time.Time); ok {
+				return t.Format(TimeLayout)
+			}
+			return nil
+		}),
+	})
+
+	// JSON is any JSON value, the text read from JSON columns is decoded before it is serialized.
+	JSON = graphql.NewScalar(graphql.ScalarConfig{
+		Name:         "JSON",
+		Description:  "The `JSON` scalar type represents an arbitrary JSON value",
+		Serialize:    serializeJSON,
+		ParseValue:   parseJSON,
+		ParseLiteral: func(valueAST ast.Value) interface{} { return parseJSON(jsonLiteral(valueAST)) },
+	})
+
+	// BigInt is a 64-bit integer serialized as a string, so clients do not lose precision,
+	// e.g. JavaScript numbers only hold 53 bits.
+	BigInt = graphql.NewScalar(graphql.ScalarConfig{
+		Name:        "BigInt",
+		Description: "The `BigInt` scalar type represents a 64-bit integer, serialized as a string",
+		Serialize:   serializeBigInt,
+		ParseValue:  parseBigInt,
+		ParseLiteral: func(valueAST ast.Value) interface{} {
+			switch valueAST := valueAST.(type) {
+			case *ast.IntValue:
+				return parseBigInt(valueAST.Value)
+			case *ast.StringValue:
+				return parseBigInt(valueAST.Value)
+			}
+			return nil
+		},
+	})
+
+	// Decimal is an exact decimal number serialized as a string, e.g. "12.30".
+	Decimal = graphql.NewScalar(graphql.ScalarConfig{
+		Name:        "Decimal",
+		Description: "The `Decimal` scalar type represents an exact decimal number, serialized as a string",
+		Serialize:   serializeDecimal,
+		ParseValue:  serializeDecimal,
+		ParseLiteral: func(valueAST ast.Value) interface{} {
+			switch valueAST := valueAST.(type) {
+			case *ast.IntValue:
+				return serializeDecimal(valueAST.Value)
+			case *ast.FloatValue:
+				return serializeDecimal(valueAST.Value)
+			case *ast.StringValue:
+				return serializeDecimal(valueAST.Value)
+			}
+			return nil
+		},
+	})
+)
+
+func parseStringLiteral(parse func(value string) interface{}) graphql.ParseLiteralFn {
+	return func(valueAST ast.Value) interface{} {
+		if valueAST, ok := valueAST.(*ast.StringValue); ok {
+			return parse(valueAST.Value)
+		}
+		return nil
+	}
+}
+
+// serializeTime formats a time.Time with layout, strings are parsed with any of the accepted layouts first,
+// e.g. the text returned by drivers that do not parse dates.
+func serializeTime(value interface{}, layout string, accepted ...string) interface{} {
+	switch v := value.(type) {
+	case time.Time:
+		return v.Format(layout)
+	case *time.Time:
+		if v == nil {
+			return nil
+		}
+		return v.Format(layout)
+	case string:
+		for _, l := range accepted {
+			if t, err := time.Parse(l, v); err == nil {
+				return t.Format(layout)
+			}
+		}
+	}
+	return nil
+}
+
+func parseTime(value interface{}, layout string) interface{} {
+	switch v := value.(type) {
+	case time.Time:
+		return v
+	case string:
+		t, err := time.Parse(layout, v)
+		if err != nil {
+			return nil
+		}
+		return t
+	}
+	return nil
+}
+
+func serializeJSON(value interface{}) interface{} {
+	var raw []byte
+	switch v := value.(type) {
+	case json.RawMessage:
+		raw = v
+	case []byte:
+		raw = v
+	case string:
+		raw = []byte(v)
+	default:
+		return value
+	}
+
+	decoder := json.NewDecoder(strings.NewReader(string(raw)))
+	decoder.UseNumber()
+	var decoded interface{}
+	if err := decoder.Decode(&decoded); err != nil {
+		return nil
+	}
+	return decoded
+}
+
+// parseJSON encodes the input value, so it can be written to JSON columns.
+func parseJSON(value interface{}) interface{} {
+	raw, err := json.Marshal(value)
+	if err != nil {
+		return nil
+	}
+	return json.RawMessage(raw)
+}
+
+// jsonLiteral converts a literal into the Go value of its JSON representation.
+func jsonLiteral(valueAST ast.Value) interface{} {
+	switch valueAST := valueAST.(type) {
+	case *ast.StringValue:
+		return valueAST.Value
+	case *ast.BooleanValue:
+		return valueAST.Value
+	case *ast.IntValue:
+		return json.Number(valueAST.Value)
+	case *ast.FloatValue:
+		return json.Number(valueAST.Value)
+	case *ast.EnumValue:
+		return valueAST.Value
+	case *ast.ListValue:
+		values := make([]interface{}, len(valueAST.Values))
+		for i, value := range valueAST.Values {
+			values[i] = jsonLiteral(value)
+		}
+		return values
+	case *ast.ObjectValue:
+		values := make(map[string]interface{}, len(valueAST.Fields))
+		for _, field := range valueAST.Fields {
+			values[field.Name.Value] = jsonLiteral(field.Value)
+		}
+		return values
+	}
+	return nil
+}
+
+func serializeBigInt(value interface{}) interface{} {
+	switch v := value.(type) {
+	case int64:
+		return strconv.FormatInt(v, 10)
+	case uint64:
+		return strconv.FormatUint(v, 10)
+	case int:
+		return strconv.Itoa(v)
+	case int32:
+		return strconv.FormatInt(int64(v), 10)
+	case string:
+		if i, ok := parseBigInt(v).(int64); ok {
+			return strconv.FormatInt(i, 10)
+		}
+		if u, err := strconv.ParseUint(v, 10, 64); err == nil {
+			return strconv.FormatUint(u, 10)
+		}
+	}
+	return nil
+}
+
+func parseBigInt(value interface{}) interface{} {
+	switch v := value.(type) {
+	case int64:
+		return v
+	case int:
+		return int64(v)
+	case float64:
+		if v != math.Trunc(v) || v < math.MinInt64 || v >= math.MaxInt64 {
+			return nil
+		}
+		return int64(v)
+	case string:
+		i, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			return nil
+		}
+		return i
+	}
+	return nil
+}
+
+var decimalPattern = regexp.MustCompile(`^[-+]?(\d+\.?\d*|\.\d+)$`)
+
+// serializeDecimal returns the decimal text of the value, strings are kept as they are,
+// so the digits stored in the database are preserved, e.g. "12.30".
+func serializeDecimal(value interface{}) interface{} {
+	switch v := value.(type) {
+	case string:
+		if !decimalPattern.MatchString(v) {
+			return nil
+		}
+		return v
+	case []byte:
+		return serializeDecimal(string(v))
+	case int64, int32, int, uint64:
+		return fmt.Sprint(v)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case float32:
+		return strconv.FormatFloat(float64(v), 'f', -1, 32)
+	}
+	return nil
+}
@@ -0,0 +1,140 @@
+package core
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/language/ast"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestScalar_Serialize(t *testing.T) {
+	at := time.Date(2024, 1, 2, 15, 4, 5, 120000000, time.UTC)
+
+	tests := []struct {
+		name      string
+		serialize func(interface{}) interface{}
+		value     interface{}
+		expected  interface{}
+	}{
+		{"DateTime time", DateTime.Serialize, at, "2024-01-02T15:04:05.12Z"},
+		{"DateTime pointer", DateTime.Serialize, &at, "2024-01-02T15:04:05.12Z"},
+		{"DateTime nil pointer", DateTime.Serialize, (*time.Time)(nil), nil},
+		{"DateTime driver text", DateTime.Serialize, "2024-01-02 15:04:05", "2024-01-02T15:04:05Z"},
+		{"DateTime invalid", DateTime.Serialize, "yesterday", nil},
+		{"Date time", Date.Serialize, at, "2024-01-02"},
+		{"Date text", Date.Serialize, "2024-01-02", "2024-01-02"},
+		{"Time time", Time.Serialize, at, "15:04:05.12"},
+		{"Time text", Time.Serialize, "15:04:05", "15:04:05"},
+		{"JSON raw", JSON.Serialize, json.RawMessage(`{"a":[1,2]}`), map[string]interface{}{"a": []interface{}{json.Number("1"), json.Number("2")}}},
+		{"JSON text", JSON.Serialize, `"x"`, "x"},
+		{"JSON invalid", JSON.Serialize, `{`, nil},
+		{"JSON value", JSON.Serialize, 1, 1},
+		{"BigInt int64", BigInt.Serialize, int64(9007199254740993), "9007199254740993"},
+		{"BigInt uint64", BigInt.Serialize, uint64(18446744073709551615), "18446744073709551615"},
+		{"BigInt int", BigInt.Serialize, 7, "7"},
+		{"BigInt text", BigInt.Serialize, "-12", "-12"},
+		{"BigInt invalid", BigInt.Serialize, "1.5", nil},
+		{"Decimal text", Decimal.Serialize, "12.30", "12.30"},
+		{"Decimal bytes", Decimal.Serialize, []byte("-0.5"), "-0.5"},
+		{"Decimal int", Decimal.Serialize, int64(3), "3"},
+		{"Decimal float", Decimal.Serialize, 0.1, "0.1"},
+		{"Decimal invalid", Decimal.Serialize, "1e3", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, tt.serialize(tt.value))
+		})
+	}
+}
+
+func TestScalar_ParseValue(t *testing.T) {
+	tests := []struct {
+		name     string
+		parse    func(interface{}) interface{}
+		value    interface{}
+		expected interface{}
+	}{
+		{"DateTime", DateTime.ParseValue, "2024-01-02T15:04:05+08:00", time.Date(2024, 1, 2, 15, 4, 5, 0, time.FixedZone("", 8*3600))},
+		{"DateTime invalid", DateTime.ParseValue, "2024-01-02", nil},
+		{"Date", Date.ParseValue, "2024-01-02", time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)},
+		{"Date invalid", Date.ParseValue, 1, nil},
+		{"Time", Time.ParseValue, "15:04:05.5", "15:04:05.5"},
+		{"Time invalid", Time.ParseValue, "25:00:00", nil},
+		{"JSON", JSON.ParseValue, map[string]interface{}{"a": 1}, json.RawMessage(`{"a":1}`)},
+		{"BigInt text", BigInt.ParseValue, "9007199254740993", int64(9007199254740993)},
+		{"BigInt float", BigInt.ParseValue, float64(12), int64(12)},
+		{"BigInt fraction", BigInt.ParseValue, 1.5, nil},
+		{"BigInt overflow", BigInt.ParseValue, "9223372036854775808", nil},
+		{"Decimal", Decimal.ParseValue, "12.30", "12.30"},
+		{"Decimal invalid", Decimal.ParseValue, "abc", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual := tt.parse(tt.value)
+			if expected, ok := tt.expected.(time.Time); ok {
+				assert.True(t, expected.Equal(actual.(time.Time)), "%v", actual)
+				return
+			}
+			assert.Equal(t, tt.expected, actual)
+		})
+	}
+}
+
+func TestScalar_ParseLiteral(t *testing.T) {
+	tests := []struct {
+		name     string
+		parse    func(ast.Value) interface{}
+		value    ast.Value
+		expected interface{}
+	}{
+		{"Date", Date.ParseLiteral, &ast.StringValue{Value: "2024-01-02"}, time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)},
+		{"Date not a string", Date.ParseLiteral, &ast.IntValue{Value: "1"}, nil},
+		{"Time", Time.ParseLiteral, &ast.StringValue{Value: "08:00:00"}, "08:00:00"},
+		{"BigInt int", BigInt.ParseLiteral, &ast.IntValue{Value: "9007199254740993"}, int64(9007199254740993)},
+		{"BigInt string", BigInt.ParseLiteral, &ast.StringValue{Value: "-1"}, int64(-1)},
+		{"BigInt float", BigInt.ParseLiteral, &ast.FloatValue{Value: "1.5"}, nil},
+		{"Decimal int", Decimal.ParseLiteral, &ast.IntValue{Value: "12"}, "12"},
+		{"Decimal float", Decimal.ParseLiteral, &ast.FloatValue{Value: "12.30"}, "12.30"},
+		{"Decimal string", Decimal.ParseLiteral, &ast.StringValue{Value: "0.10"}, "0.10"},
+		{"Decimal boolean", Decimal.ParseLiteral, &ast.BooleanValue{Value: true}, nil},
+		{"JSON object", JSON.ParseLiteral, &ast.ObjectValue{Fields: []*ast.ObjectField{
+			{Name: &ast.Name{Value: "a"}, Value: &ast.ListValue{Values: []ast.Value{&ast.IntValue{Value: "1"}, &ast.EnumValue{Value: "B"}}}},
+		}}, json.RawMessage(`{"a":[1,"B"]}`)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual := tt.parse(tt.value)
+			if expected, ok := tt.expected.(time.Time); ok {
+				assert.True(t, expected.Equal(actual.(time.Time)), "%v", actual)
+				return
+			}
+			assert.Equal(t, tt.expected, actual)
+		})
+	}
+}
+
+func TestRegistry_RegisterScalar(t *testing.T) {
+	money := graphql.NewScalar(graphql.ScalarConfig{
+		Name:      "Money",
+		Serialize: func(value interface{}) interface{} { return value },
+	})
+	a, b := NewRegistry(), NewRegistry()
+	a.RegisterScalar("Money", money)
+	a.RegisterScalar(FieldTypeDateTime, graphql.String)
+
+	scalar, ok := a.ScalarType("Money")
+	assert.True(t, ok)
+	assert.Same(t, money, scalar)
+	scalar, _ = a.ScalarType(FieldTypeDateTime)
+	assert.Same(t, graphql.String, scalar)
+
+	// the scalars of a registry do not leak into another one.
+	_, ok = b.ScalarType("Money")
+	assert.False(t, ok)
+	scalar, ok = b.ScalarType(FieldTypeDateTime)
+	assert.True(t, ok)
+	assert.Same(t, DateTime, scalar)
+}
@@ -0,0 +1,263 @@
+package core
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sync"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/gqlerrors"
+)
+
+// Querier is implemented by *sql.DB and *sql.Tx, resolvers run their statements with it,
+// so the same code works inside and outside a transaction, see NodeRegistry.Querier.
+type Querier interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+var _ Querier = (*sql.DB)(nil)
+var _ Querier = (*sql.Tx)(nil)
+
+type transactionKey struct{}
+
+// transaction is shared by the resolvers of one operation, the first mutation field activates it,
+// from then on the queries are sent to the primary databases.
+// When the registry is transactional, a transaction is begun on the data source used afterward,
+// the first failing field rolls it back and it is committed when the execution finishes.
+// A transaction never spans several data sources, their commits could not be atomic together.
+type transaction struct {
+	registry      *NodeRegistry
+	transactional bool
+
+	lock sync.Mutex
+	// active 为 true 时已经开始执行 mutation field, 查询 operation 不会开启事务.
+	active bool
+	// tx 在 source 数据源上开启的事务.
+	tx     *sql.Tx
+	source string
+	// done 为 true 时事务已经提交或者回滚.
+	done bool
+	// failedField 第一个失败的 mutation field.
+	failedField string
+	// succeededFields 已经成功执行的 mutation field 的 response key, 回滚时它们的结果将被清空.
+	succeededFields []string
+}
+
+func transactionFromContext(ctx context.Context) *transaction {
+	if ctx == nil {
+		return nil
+	}
+	t, _ := ctx.Value(transactionKey{}).(*transaction)
+	return t
+}
+
+// start is called before every mutation field, it fails when a previous field has rolled back the transaction.
+func (t *transaction) start() error {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	if t.transactional && t.failedField != "" {
+		return fmt.Errorf("skipped: the transaction was rolled back because mutation %s failed", t.failedField)
+	}
+	t.active = true
+	return nil
+}
+
+// mutating reports whether a mutation field of the operation has started.
+func (t *transaction) mutating() bool {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	return t.active
+}
+
+// querier returns the transaction of the data source, it is begun on the first use.
+// The primary database is returned outside the transaction.
+// Using another data source in the same transaction is an error, so the mutation fails and the transaction is rolled back.
+func (t *transaction) querier(ctx context.Context, source *DataSource) (Querier, error) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	if !t.active || !t.transactional {
+		return source.DB(), nil
+	}
+	if t.done {
+		return nil, fmt.Errorf("the transaction is already finished")
+	}
+	if t.tx != nil {
+		if t.source != source.Name() {
+			return nil, fmt.Errorf("the transaction on data source %s can not span data source %s", t.source, source.Name())
+		}
+		return t.tx, nil
+	}
+	tx, err := source.DB().BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	t.tx = tx
+	t.source = source.Name()
+	return tx, nil
+}
+
+func (t *transaction) succeed(field string) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	t.succeededFields = append(t.succeededFields, field)
+}
+
+func (t *transaction) fail(field string) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	if !t.transactional || t.failedField != "" {
+		return
+	}
+	t.failedField = field
+	t.rollback()
+}
+
+func (t *transaction) rollback() {
+	if t.done {
+		return
+	}
+	t.done = true
+	if t.tx != nil {
+		_ = t.tx.Rollback()
+	}
+}
+
+func (t *transaction) commit() error {
+	t.done = true
+	if t.tx == nil {
+		return nil
+	}
+	if err := t.tx.Commit(); err != nil {
+		return fmt.Errorf("data source %s: %s", t.source, err.Error())
+	}
+	return nil
+}
+
+// finish commits the transaction, when the commit fails or the transaction was rolled back,
+// the results of the succeeded mutation fields are cleared since none of them is persisted.
+func (t *transaction) finish(result *graphql.Result) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	if !t.transactional {
+		return
+	}
+
+	reason := ""
+	if t.failedField != "" {
+		reason = fmt.Sprintf("rolled back because mutation %s failed", t.failedField)
+	} else if !t.done {
+		if err := t.commit(); err != nil {
+			reason = fmt.Sprintf("commit failed: %s", err.Error())
+		}
+	}
+	if reason == "" {
+		return
+	}
+
+	data, _ := result.Data.(map[string]interface{})
+	for _, field := range t.succeededFields {
+		if data != nil {
+			data[field] = nil
+		}
+		result.Errors = append(result.Errors, gqlerrors.FormattedError{
+			Message: fmt.Sprintf("mutation %s is %s", field, reason),
+			Path:    []interface{}{field},
+		})
+	}
+}
+
+// wrapMutation marks the operation as a mutation before the resolver runs,
+// and runs the resolver inside the transaction of the operation when the registry is transactional.
+func wrapMutation(resolve graphql.FieldResolveFn) graphql.FieldResolveFn {
+	if resolve == nil {
+		resolve = graphql.DefaultResolveFn
+	}
+	return func(p graphql.ResolveParams) (interface{}, error) {
+		t := transactionFromContext(p.Context)
+		if t == nil {
+			return resolve(p)
+		}
+
+		field := p.Info.FieldName
+		if p.Info.Path != nil {
+			if key, ok := p.Info.Path.Key.(string); ok {
+				field = key
+			}
+		}
+
+		if err := t.start(); err != nil {
+			return nil, err
+		}
+		return t.record(field, func() (interface{}, error) { return resolve(p) })
+	}
+}
+
+// record runs the resolver of the mutation field and records whether it failed, a panic fails the field
+// before graphql-go recovers it, the error of a returned thunk is recorded when the thunk is resolved.
+func (t *transaction) record(field string, resolve func() (interface{}, error)) (interface{}, error) {
+	defer func() {
+		if r := recover(); r != nil {
+			t.fail(field)
+			panic(r)
+		}
+	}()
+
+	result, err := resolve()
+	if err != nil {
+		t.fail(field)
+		return nil, err
+	}
+	if thunk, ok := result.(func() (interface{}, error)); ok {
+		return func() (interface{}, error) { return t.record(field, thunk) }, nil
+	}
+	t.succeed(field)
+	return result, nil
+}
+
+var _ graphql.Extension = (*mutationExtension)(nil)
+
+// mutationExtension attaches a transaction to the context of every execution,
+// it is only activated when a mutation field is resolved.
+type mutationExtension struct {
+	registry      *NodeRegistry
+	transactional bool
+}
+
+func (e *mutationExtension) Init(ctx context.Context, _ *graphql.Params) context.Context {
+	return ctx
+}
+
+func (e *mutationExtension) Name() string {
+	return "Mutation"
+}
+
+func (e *mutationExtension) ParseDidStart(ctx context.Context) (context.Context, graphql.ParseFinishFunc) {
+	return ctx, func(error) {}
+}
+
+func (e *mutationExtension) ValidationDidStart(ctx context.Context) (context.Context, graphql.ValidationFinishFunc) {
+	return ctx, func([]gqlerrors.FormattedError) {}
+}
+
+func (e *mutationExtension) ExecutionDidStart(ctx context.Context) (context.Context, graphql.ExecutionFinishFunc) {
+	t := &transaction{registry: e.registry, transactional: e.transactional}
+	return context.WithValue(ctx, transactionKey{}, t), t.finish
+}
+
+func (e *mutationExtension) ResolveFieldDidStart(ctx context.Context, _ *graphql.ResolveInfo) (context.Context, graphql.ResolveFieldFinishFunc) {
+	return ctx, func(interface{}, error) {}
+}
+
+func (e *mutationExtension) HasResult() bool {
+	return false
+}
+
+func (e *mutationExtension) GetResult(context.Context) interface{} {
+	return nil
+}
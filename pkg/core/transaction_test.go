@@ -0,0 +1,290 @@
+package core
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/graphql-go/graphql"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Finovate/go-gql-builder/pkg/core/argument"
+)
+
+// stubLog records the statements of the stub databases of a test.
+type stubLog struct {
+	mu         sync.Mutex
+	statements []string
+}
+
+func (l *stubLog) add(statement string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.statements = append(l.statements, statement)
+}
+
+func (l *stubLog) Statements() []string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return append([]string(nil), l.statements...)
+}
+
+// stubDB is a database recording its statements, the statements containing FAIL return an error.
+type stubDB struct {
+	name       string
+	log        *stubLog
+	failCommit bool
+}
+
+func (s *stubDB) Connect(context.Context) (driver.Conn, error) { return &stubConn{db: s}, nil }
+func (s *stubDB) Driver() driver.Driver                        { return nil }
+
+type stubConn struct {
+	db *stubDB
+}
+
+func (c *stubConn) Prepare(string) (driver.Stmt, error) {
+	return nil, fmt.Errorf("prepare is not supported")
+}
+func (c *stubConn) Close() error { return nil }
+func (c *stubConn) Begin() (driver.Tx, error) {
+	c.db.log.add(c.db.name + ": BEGIN")
+	return &stubTx{db: c.db}, nil
+}
+
+func (c *stubConn) ExecContext(_ context.Context, query string, _ []driver.NamedValue) (driver.Result, error) {
+	c.db.log.add(c.db.name + ": " + query)
+	if strings.Contains(query, "FAIL") {
+		return nil, fmt.Errorf("exec failed")
+	}
+	return driver.RowsAffected(1), nil
+}
+
+func (c *stubConn) QueryContext(_ context.Context, query string, _ []driver.NamedValue) (driver.Rows, error) {
+	c.db.log.add(c.db.name + ": " + query)
+	return &stubRows{}, nil
+}
+
+type stubTx struct {
+	db *stubDB
+}
+
+func (t *stubTx) Commit() error {
+	t.db.log.add(t.db.name + ": COMMIT")
+	if t.db.failCommit {
+		return fmt.Errorf("connection lost")
+	}
+	return nil
+}
+
+func (t *stubTx) Rollback() error {
+	t.db.log.add(t.db.name + ": ROLLBACK")
+	return nil
+}
+
+type stubRows struct{}
+
+func (r *stubRows) Columns() []string           { return nil }
+func (r *stubRows) Close() error                { return nil }
+func (r *stubRows) Next(_ []driver.Value) error { return io.EOF }
+
+// thingNode is a Node without data source, the schema requires a query field.
+type thingNode struct {
+	BaseNode
+}
+
+func (n *thingNode) Name() string                    { return "things" }
+func (n *thingNode) Type() FieldType                 { return "thing" }
+func (n *thingNode) IsList() bool                    { return true }
+func (n *thingNode) Resolve() graphql.FieldResolveFn { return nil }
+func (n *thingNode) BuildArgs() []argument.Argument  { return nil }
+func (n *thingNode) BuildFields() []*Field {
+	return []*Field{NewNodeField("name", FieldTypeString)}
+}
+
+// newTransactionRegistry returns a transactional registry whose mutation exec(source, statement)
+// runs the statement on the data source, the data sources are default and crm.
+// The statements starting with PANIC panic, the ones starting with LATER are run by a thunk.
+func newTransactionRegistry(t *testing.T) (*NodeRegistry, *stubLog, map[string]*stubDB) {
+	log := &stubLog{}
+	stubs := map[string]*stubDB{
+		DefaultDataSource: {name: DefaultDataSource, log: log},
+		"crm":             {name: "crm", log: log},
+	}
+
+	registry := NewRegistry()
+	registry.Register(&thingNode{})
+	for name, stub := range stubs {
+		db := sql.OpenDB(stub)
+		t.Cleanup(func() { _ = db.Close() })
+		registry.RegisterDataSource(name, db)
+	}
+	registry.EnableMutationTransaction()
+	registry.RegisterMutation("exec", &graphql.Field{
+		Type: graphql.String,
+		Args: graphql.FieldConfigArgument{
+			"source":    &graphql.ArgumentConfig{Type: graphql.String},
+			"statement": &graphql.ArgumentConfig{Type: graphql.String},
+		},
+		Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+			source, _ := p.Args["source"].(string)
+			statement, _ := p.Args["statement"].(string)
+			run := func() (interface{}, error) {
+				querier, err := registry.Querier(p.Context, source)
+				if err != nil {
+					return nil, err
+				}
+				if _, err := querier.ExecContext(p.Context, statement); err != nil {
+					return nil, err
+				}
+				return statement, nil
+			}
+			switch {
+			case strings.HasPrefix(statement, "PANIC"):
+				panic(statement)
+			case strings.HasPrefix(statement, "LATER"):
+				// the statement is run when the thunk is resolved.
+				return run, nil
+			}
+			return run()
+		},
+	})
+	return registry, log, stubs
+}
+
+// executeQuery runs the query against the registry, it returns the data and the error messages.
+func executeQuery(t *testing.T, registry *NodeRegistry, query string) (map[string]interface{}, []string) {
+	t.Helper()
+	h, err := registry.BuildHandler()
+	if err != nil {
+		t.Fatalf("BuildHandler failed: %v", err)
+	}
+
+	body, _ := json.Marshal(map[string]interface{}{"query": query})
+	req := httptest.NewRequest("POST", "/graphql", strings.NewReader(string(body)))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+
+	var result struct {
+		Data   map[string]interface{} `json:"data"`
+		Errors []struct {
+			Message string `json:"message"`
+		} `json:"errors"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &result); err != nil {
+		t.Fatalf("invalid response %q: %v", w.Body.String(), err)
+	}
+	messages := make([]string, len(result.Errors))
+	for i, e := range result.Errors {
+		messages[i] = e.Message
+	}
+	return result.Data, messages
+}
+
+func TestTransaction_SingleDataSource(t *testing.T) {
+	registry, log, _ := newTransactionRegistry(t)
+
+	data, errs := executeQuery(t, registry, `mutation {
+		a: exec(statement: "INSERT a")
+		b: exec(source: "crm", statement: "INSERT b")
+	}`)
+	assert.Equal(t, map[string]interface{}{"a": nil, "b": nil}, data)
+	assert.Equal(t, []string{
+		"the transaction on data source default can not span data source crm",
+		"mutation a is rolled back because mutation b failed",
+	}, errs)
+	// the second data source is never written.
+	assert.Equal(t, []string{"default: BEGIN", "default: INSERT a", "default: ROLLBACK"}, log.Statements())
+}
+
+func TestTransaction_Commit(t *testing.T) {
+	registry, log, _ := newTransactionRegistry(t)
+
+	data, errs := executeQuery(t, registry, `mutation {
+		a: exec(statement: "INSERT a")
+		b: exec(statement: "INSERT b")
+	}`)
+	assert.Empty(t, errs)
+	assert.Equal(t, map[string]interface{}{"a": "INSERT a", "b": "INSERT b"}, data)
+	assert.Equal(t, []string{"default: BEGIN", "default: INSERT a", "default: INSERT b", "default: COMMIT"}, log.Statements())
+}
+
+func TestTransaction_RollbackOnFailure(t *testing.T) {
+	registry, log, _ := newTransactionRegistry(t)
+
+	data, errs := executeQuery(t, registry, `mutation {
+		a: exec(statement: "INSERT a")
+		b: exec(statement: "INSERT FAIL")
+		c: exec(statement: "INSERT c")
+	}`)
+	// the result of the succeeded field is cleared, the fields after the failing one are skipped.
+	assert.Equal(t, map[string]interface{}{"a": nil, "b": nil, "c": nil}, data)
+	assert.Equal(t, []string{
+		"exec failed",
+		"skipped: the transaction was rolled back because mutation b failed",
+		"mutation a is rolled back because mutation b failed",
+	}, errs)
+	assert.Equal(t, []string{"default: BEGIN", "default: INSERT a", "default: INSERT FAIL", "default: ROLLBACK"}, log.Statements())
+}
+
+func TestTransaction_CommitFailure(t *testing.T) {
+	registry, log, stubs := newTransactionRegistry(t)
+	stubs[DefaultDataSource].failCommit = true
+
+	data, errs := executeQuery(t, registry, `mutation {
+		a: exec(statement: "INSERT a")
+		b: exec(statement: "INSERT b")
+	}`)
+	assert.Equal(t, map[string]interface{}{"a": nil, "b": nil}, data)
+	assert.Equal(t, []string{
+		"mutation a is commit failed: data source default: connection lost",
+		"mutation b is commit failed: data source default: connection lost",
+	}, errs)
+	assert.Equal(t, []string{"default: BEGIN", "default: INSERT a", "default: INSERT b", "default: COMMIT"}, log.Statements())
+}
+
+func TestTransaction_RollbackOnPanic(t *testing.T) {
+	registry, log, _ := newTransactionRegistry(t)
+
+	data, errs := executeQuery(t, registry, `mutation {
+		a: exec(statement: "INSERT a")
+		b: exec(statement: "PANIC b")
+	}`)
+	assert.Equal(t, map[string]interface{}{"a": nil, "b": nil}, data)
+	assert.Len(t, errs, 2)
+	assert.Equal(t, "mutation a is rolled back because mutation b failed", errs[1])
+	assert.Equal(t, []string{"default: BEGIN", "default: INSERT a", "default: ROLLBACK"}, log.Statements())
+}
+
+func TestTransaction_Thunk(t *testing.T) {
+	registry, log, _ := newTransactionRegistry(t)
+
+	data, errs := executeQuery(t, registry, `mutation {
+		a: exec(statement: "LATER INSERT a")
+		b: exec(statement: "LATER INSERT b")
+	}`)
+	assert.Empty(t, errs)
+	assert.Equal(t, map[string]interface{}{"a": "LATER INSERT a", "b": "LATER INSERT b"}, data)
+	// graphql-go decides the order in which the thunks are resolved.
+	statements := log.Statements()
+	assert.Equal(t, "default: COMMIT", statements[len(statements)-1])
+	assert.ElementsMatch(t, []string{"default: BEGIN", "default: LATER INSERT a", "default: LATER INSERT b", "default: COMMIT"}, statements)
+
+	// the error of the thunk fails the field.
+	registry, log, _ = newTransactionRegistry(t)
+	data, errs = executeQuery(t, registry, `mutation {
+		a: exec(statement: "INSERT a")
+		b: exec(statement: "LATER FAIL b")
+	}`)
+	assert.Equal(t, map[string]interface{}{"a": nil, "b": nil}, data)
+	assert.Equal(t, []string{"exec failed", "mutation a is rolled back because mutation b failed"}, errs)
+	assert.Equal(t, []string{"default: BEGIN", "default: INSERT a", "default: LATER FAIL b", "default: ROLLBACK"}, log.Statements())
+}
@@ -0,0 +1,173 @@
+package dialect
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Dialect renders the parts of a SQL statement that differ between databases.
+// Statements are built with "?" placeholders and unquoted identifiers first, then rendered with the Dialect
+// of the registry or of the adapter, so the same Node definitions run against any supported database.
+type Dialect interface {
+	// Name of the dialect, e.g. mysql.
+	Name() string
+	// Placeholder returns the placeholder of the n-th bind argument, n starts from 1.
+	Placeholder(n int) string
+	// QuoteIdentifier quotes a table or column name, every part of a qualified name is quoted, e.g. crm.user.
+	QuoteIdentifier(name string) string
+	// Limit returns the clause selecting count rows after skipping offset rows.
+	Limit(count, offset int) string
+	// BoolLiteral returns the literal of a boolean value.
+	BoolLiteral(value bool) string
+	// CaseInsensitiveLike returns a condition matching the column against the LIKE pattern ignoring case.
+	CaseInsensitiveLike(column, pattern string) string
+	// Returning returns the clause making an INSERT statement return the column,
+	// it is empty when the database reports the generated id through sql.Result.LastInsertId.
+	Returning(column string) string
+}
+
+var (
+	MySQL      Dialect = mysql{}
+	PostgreSQL Dialect = postgres{}
+	SQLite     Dialect = sqlite{}
+)
+
+// Default is used when neither the registry nor the adapter selects a dialect.
+var Default = MySQL
+
+// ByName returns the dialect of the name, the names of the database/sql drivers are accepted as well.
+func ByName(name string) (Dialect, error) {
+	switch strings.ToLower(name) {
+	case "mysql":
+		return MySQL, nil
+	case "postgres", "postgresql", "pgx":
+		return PostgreSQL, nil
+	case "sqlite", "sqlite3":
+		return SQLite, nil
+	default:
+		return nil, fmt.Errorf("unsupported sql dialect: %s", name)
+	}
+}
+
+// Rebind replaces the "?" placeholders of the statement with the placeholders of the dialect,
+// question marks inside string literals and quoted identifiers are left untouched.
+func Rebind(d Dialect, query string) string {
+	if d.Placeholder(1) == "?" || !strings.Contains(query, "?") {
+		return query
+	}
+
+	var builder strings.Builder
+	builder.Grow(len(query) + 8)
+	var quote rune
+	n := 0
+	for _, r := range query {
+		switch {
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			}
+		case r == '\'' || r == '"' || r == '`':
+			quote = r
+		case r == '?':
+			n++
+			builder.WriteString(d.Placeholder(n))
+			continue
+		}
+		builder.WriteRune(r)
+	}
+	return builder.String()
+}
+
+func quoteParts(name string, quote string) string {
+	parts := strings.Split(name, ".")
+	for i, part := range parts {
+		parts[i] = quote + strings.ReplaceAll(part, quote, quote+quote) + quote
+	}
+	return strings.Join(parts, ".")
+}
+
+type mysql struct{}
+
+func (mysql) Name() string { return "mysql" }
+
+func (mysql) Placeholder(int) string { return "?" }
+
+func (mysql) QuoteIdentifier(name string) string {
+	return quoteParts(name, "`")
+}
+
+func (mysql) Limit(count, offset int) string {
+	return fmt.Sprintf("LIMIT %d,%d", offset, count)
+}
+
+func (mysql) BoolLiteral(value bool) string {
+	if value {
+		return "1"
+	}
+	return "0"
+}
+
+func (mysql) CaseInsensitiveLike(column, pattern string) string {
+	return fmt.Sprintf("LOWER(%s) LIKE LOWER(%s)", column, pattern)
+}
+
+func (mysql) Returning(string) string { return "" }
+
+type postgres struct{}
+
+func (postgres) Name() string { return "postgres" }
+
+func (postgres) Placeholder(n int) string { return "$" + strconv.Itoa(n) }
+
+func (postgres) QuoteIdentifier(name string) string {
+	return quoteParts(name, `"`)
+}
+
+func (postgres) Limit(count, offset int) string {
+	return fmt.Sprintf("LIMIT %d OFFSET %d", count, offset)
+}
+
+func (postgres) BoolLiteral(value bool) string {
+	if value {
+		return "TRUE"
+	}
+	return "FALSE"
+}
+
+func (postgres) CaseInsensitiveLike(column, pattern string) string {
+	return fmt.Sprintf("%s ILIKE %s", column, pattern)
+}
+
+// Returning lib/pq and pgx do not support LastInsertId, the generated id is returned by the statement instead.
+func (postgres) Returning(column string) string {
+	return " RETURNING " + column
+}
+
+type sqlite struct{}
+
+func (sqlite) Name() string { return "sqlite" }
+
+func (sqlite) Placeholder(int) string { return "?" }
+
+func (sqlite) QuoteIdentifier(name string) string {
+	return quoteParts(name, `"`)
+}
+
+func (sqlite) Limit(count, offset int) string {
+	return fmt.Sprintf("LIMIT %d OFFSET %d", count, offset)
+}
+
+func (sqlite) BoolLiteral(value bool) string {
+	if value {
+		return "1"
+	}
+	return "0"
+}
+
+// CaseInsensitiveLike LIKE of SQLite ignores the case of ASCII characters only, both sides are lowered.
+func (sqlite) CaseInsensitiveLike(column, pattern string) string {
+	return fmt.Sprintf("LOWER(%s) LIKE LOWER(%s)", column, pattern)
+}
+
+func (sqlite) Returning(string) string { return "" }
@@ -0,0 +1,21 @@
+package dialect
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRebind(t *testing.T) {
+	query := `SELECT "a?" FROM t WHERE b = ? AND c LIKE '?%' AND d IN (?,?)`
+
+	assert.Equal(t, query, Rebind(MySQL, query))
+	assert.Equal(t, `SELECT "a?" FROM t WHERE b = $1 AND c LIKE '?%' AND d IN ($2,$3)`, Rebind(PostgreSQL, query))
+}
+
+func TestQuoteIdentifier(t *testing.T) {
+	assert.Equal(t, "`crm`.`user`", MySQL.QuoteIdentifier("crm.user"))
+	assert.Equal(t, `"we""ird"`, PostgreSQL.QuoteIdentifier(`we"ird`))
+	assert.Equal(t, "LIMIT 0,10", MySQL.Limit(10, 0))
+	assert.Equal(t, "LIMIT 10 OFFSET 0", SQLite.Limit(10, 0))
+}